@@ -1,56 +1,557 @@
 package maze
 
 import (
-	"math/rand"
+	"context"
 )
 
-func getUnvisited(g, maze Graph) Node {
-	if g.NodeCount() == maze.NodeCount() {
+// The generators in this file accept any Graph. If g is not connected, a
+// separate maze is made for each of its connected components, and the result
+// is a spanning forest rather than a spanning tree. Use CheckConnected() to
+// reject such graphs instead.
+//
+// Each generator has a variant taking an RNG, and a Context variant taking
+// Options, which returns ctx.Err() if ctx is done before the maze is
+// finished, or a *BudgetError if g has more than Options.MaxNodes nodes.
+
+// Wilson implements Wilson's algorithm.
+// see: http://weblog.jamisbuck.org/2011/1/20/maze-generation-wilson-s-algorithm.html
+func Wilson(g Graph) Graph {
+	return WilsonRand(g, defaultRNG)
+}
+
+// WilsonRand is Wilson using rng as the source of randomness.
+func WilsonRand(g Graph, rng RNG) Graph {
+	maze, _ := WilsonContext(context.Background(), g, Options{Rand: rng})
+	return maze
+}
+
+// WilsonContext is Wilson controlled by ctx and opts.
+func WilsonContext(ctx context.Context, g Graph, opts Options) (Graph, error) {
+	return build(ctx, g.NodeCount(), opts, func(b *builder) {
+		wilson(b, g)
+	})
+}
+
+func wilson(b *builder, g Graph) {
+	// keeping the unvisited nodes in a set means picking the start of each
+	// walk takes constant time, no matter how few are left.
+	unvisited := newIndexedSet(g.Nodes())
+
+	// a walk can only end by reaching the maze, so each connected component
+	// of g needs a node in the maze to begin with.
+	for _, component := range Components(g) {
+		n := component[b.rng.Intn(len(component))]
+		b.add(n) // add random initial node to maze
+		unvisited.Remove(n)
+	}
+
+	// while there are unvisited nodes, create random acyclic walks
+	// through g and add those paths to maze.
+	pos := make(map[Node]int)
+	for n := unvisited.Random(b.rng); n != nil; n = unvisited.Random(b.rng) {
+		path := loopErasedWalk(b, g, n, b.maze, pos)
+
+		// add the path to the maze
+		for i := 0; i < len(path)-1; i++ {
+			b.carve(path[i], path[i+1])
+			unvisited.Remove(path[i])
+		}
+
+		if b.stopped() {
+			return
+		}
+	}
+}
+
+// LoopErasedRandomWalk makes a random walk through g from start until it
+// reaches a node in stop. Whenever the walk crosses itself the loop is erased,
+// so the returned path from start to the node in stop never visits a node
+// twice. If no node in stop can be reached from start, nil is returned.
+func LoopErasedRandomWalk(g Graph, start Node, stop NodeSet) NodeSlice {
+	return LoopErasedRandomWalkRand(g, start, stop, defaultRNG)
+}
+
+// LoopErasedRandomWalkRand is LoopErasedRandomWalk using rng as the source of
+// randomness.
+//
+// Checking that stop can be reached takes a breadth first search, which may
+// look at every node connected to start. To make many short walks through a
+// large graph, use LoopErasedRandomWalkContext instead.
+func LoopErasedRandomWalkRand(g Graph, start Node, stop NodeSet, rng RNG) NodeSlice {
+	if !reaches(g, start, stop) {
+		return nil // the walk would never end
+	}
+	b := &builder{ctx: context.Background(), rng: rng}
+	return loopErasedWalk(b, g, start, stop, make(map[Node]int))
+}
+
+// LoopErasedRandomWalkContext is LoopErasedRandomWalk controlled by ctx and
+// opts. It doesn't check that stop can be reached from start, so each call
+// only costs as much as the walk. If stop can't be reached, the walk goes on
+// until ctx is done, and then returns nil and ctx.Err(). The exception is a
+// start with no neighbors, for which nil is returned at once.
+func LoopErasedRandomWalkContext(ctx context.Context, g Graph, start Node, stop NodeSet, opts Options) (NodeSlice, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if !stop.Has(start) && len(g.Neighbors(start)) == 0 {
+		return nil, nil // the walk can't take a step
+	}
+	b := &builder{ctx: ctx, opts: opts, rng: opts.rand()}
+	path := loopErasedWalk(b, g, start, stop, make(map[Node]int))
+	return path, b.err
+}
+
+// loopErasedWalk does the work of LoopErasedRandomWalk, without checking
+// that stop can be reached. pos must be empty, and is left empty on return so
+// it can be reused by the next walk. If b is stopped, nil is returned.
+func loopErasedWalk(b *builder, g Graph, start Node, stop NodeSet, pos map[Node]int) NodeSlice {
+	// pos holds the position of each node in the path, so that finding and
+	// erasing a loop doesn't require searching the path.
+	path := NodeSlice{start}
+	pos[start] = 0
+	for n := start; !stop.Has(n) && !b.stopped(); {
+		neighbors := g.Neighbors(n)
+		next := neighbors[b.rng.Intn(len(neighbors))] // get random neighbor
+		b.emit(WalkStep, n, next)
+		n = next
+
+		if prev, in := pos[n]; in {
+			// it's already in the path, we have a loop.
+			// must cut loop out
+			b.emit(LoopErased, n, nil)
+			for _, m := range path[prev+1:] {
+				delete(pos, m)
+			}
+			path = path[:prev+1]
+			continue
+		}
+		pos[n] = len(path)
+		path = path.Append(n)
+	}
+
+	for _, n := range path {
+		delete(pos, n)
+	}
+	if b.err != nil {
 		return nil
 	}
+	return path
+}
+
+// Backtracker implements the recursive backtracker algorithm, which is a
+// randomized depth-first search. An explicit stack is used instead of
+// recursion so that very large graphs do not exhaust the call stack.
+// see: http://weblog.jamisbuck.org/2010/12/27/maze-generation-recursive-backtracking
+func Backtracker(g Graph) Graph {
+	return BacktrackerRand(g, defaultRNG)
+}
+
+// BacktrackerRand is Backtracker using rng as the source of randomness.
+func BacktrackerRand(g Graph, rng RNG) Graph {
+	maze, _ := BacktrackerContext(context.Background(), g, Options{Rand: rng})
+	return maze
+}
+
+// BacktrackerContext is Backtracker controlled by ctx and opts.
+func BacktrackerContext(ctx context.Context, g Graph, opts Options) (Graph, error) {
+	return build(ctx, g.NodeCount(), opts, func(b *builder) {
+		backtracker(b, g)
+	})
+}
+
+func backtracker(b *builder, g Graph) {
+	var stack, unvisited NodeSlice
+	for _, component := range Components(g) {
+		n := component[b.rng.Intn(len(component))]
+		b.add(n)
+		stack = stack.Append(n)
+
+		// look at the node on top of the stack. carve a passage to a random
+		// unvisited neighbor and push it, or pop the node if it has none.
+		for len(stack) > 0 {
+			if b.stopped() {
+				return
+			}
+			n = stack[len(stack)-1]
 
-	for {
-		n := g.RandomNode()
-		if !maze.Has(n) {
-			return n
+			unvisited = unvisited[:0]
+			for _, neighbor := range g.Neighbors(n) {
+				if !b.maze.Has(neighbor) {
+					unvisited = unvisited.Append(neighbor)
+				}
+			}
+
+			if len(unvisited) == 0 {
+				stack, _ = stack.pop() // dead end. backtrack.
+				continue
+			}
+
+			next := unvisited[b.rng.Intn(len(unvisited))]
+			b.carve(n, next)
+			stack = stack.Append(next)
 		}
 	}
 }
 
-// Wilson implements Wilson's algorithm.
-// see: http://weblog.jamisbuck.org/2011/1/20/maze-generation-wilson-s-algorithm.html
-func Wilson(g Graph) (maze Graph) {
-	maze = NewMapGraph()
-	if n := getUnvisited(g, maze); n != nil {
-		maze.Add(n) // add random initial node to maze
+// Kruskal implements randomized Kruskal's algorithm. The edges of g are
+// shuffled and then added to the maze whenever they join two parts of the
+// maze which are not yet connected.
+// see: http://weblog.jamisbuck.org/2011/1/3/maze-generation-kruskal-s-algorithm
+func Kruskal(g Graph) Graph {
+	return KruskalRand(g, defaultRNG)
+}
+
+// KruskalRand is Kruskal using rng as the source of randomness.
+func KruskalRand(g Graph, rng RNG) Graph {
+	maze, _ := KruskalContext(context.Background(), g, Options{Rand: rng})
+	return maze
+}
+
+// KruskalContext is Kruskal controlled by ctx and opts.
+func KruskalContext(ctx context.Context, g Graph, opts Options) (Graph, error) {
+	return build(ctx, g.NodeCount(), opts, func(b *builder) {
+		kruskal(b, g)
+	})
+}
+
+func kruskal(b *builder, g Graph) {
+	nodes := g.Nodes()
+	edges := Edges(g)
+	shuffle(b.rng, len(edges), func(i, j int) {
+		edges[i], edges[j] = edges[j], edges[i]
+	})
+
+	sets := NewDisjointSet()
+	sets.Add(nodes...)
+	for _, e := range edges {
+		if sets.Count() == 1 {
+			break // everything is connected. never true if g isn't.
+		}
+		if b.stopped() {
+			return
+		}
+		if sets.Union(e.A, e.B) {
+			b.carve(e.A, e.B)
+		}
 	}
 
-	// while there are unvisited nodes, create random acyclic walks
-	// through g and add those paths to maze.
-	for n := getUnvisited(g, maze); n != nil; n = getUnvisited(g, maze) {
+	b.add(nodes...) // nodes without edges
+}
+
+// WeightFunc gives the weight of the edge between a and b. It should return
+// the same value for (a, b) and (b, a) every time it is called.
+type WeightFunc func(a, b Node) float64
+
+// RandomWeights makes a WeightFunc which gives each edge a random weight in
+// [0,1) from rng. The weight is chosen the first time an edge is seen and is
+// remembered afterward.
+func RandomWeights(rng RNG) WeightFunc {
+	weights := make(map[Edge]float64)
+	return func(a, b Node) float64 {
+		if w, in := weights[Edge{A: b, B: a}]; in {
+			return w
+		}
+		e := Edge{A: a, B: b}
+		w, in := weights[e]
+		if !in {
+			w = rng.Float64()
+			weights[e] = w
+		}
+		return w
+	}
+}
+
+// Prim implements the "simplified" version of Prim's algorithm, where
+// each step picks a uniformly random edge from the frontier of the maze.
+// see: http://weblog.jamisbuck.org/2011/1/10/maze-generation-prim-s-algorithm
+func Prim(g Graph) Graph {
+	return PrimWeightedRand(g, nil, defaultRNG)
+}
+
+// PrimRand is Prim using rng as the source of randomness.
+func PrimRand(g Graph, rng RNG) Graph {
+	return PrimWeightedRand(g, nil, rng)
+}
+
+// PrimContext is Prim controlled by ctx and opts.
+func PrimContext(ctx context.Context, g Graph, opts Options) (Graph, error) {
+	return PrimWeightedContext(ctx, g, nil, opts)
+}
+
+// PrimWeighted implements Prim's algorithm, growing the maze from a random
+// node by always adding the frontier edge with the lowest weight. Using
+// RandomWeights() gives "true" Prim's algorithm. If weight is nil, a
+// frontier edge is instead picked uniformly at random, as in Prim().
+func PrimWeighted(g Graph, weight WeightFunc) Graph {
+	return PrimWeightedRand(g, weight, defaultRNG)
+}
+
+// PrimWeightedRand is PrimWeighted using rng as the source of randomness.
+func PrimWeightedRand(g Graph, weight WeightFunc, rng RNG) Graph {
+	maze, _ := PrimWeightedContext(context.Background(), g, weight, Options{Rand: rng})
+	return maze
+}
+
+// PrimWeightedContext is PrimWeighted controlled by ctx and opts.
+func PrimWeightedContext(ctx context.Context, g Graph, weight WeightFunc, opts Options) (Graph, error) {
+	return build(ctx, g.NodeCount(), opts, func(b *builder) {
+		prim(b, g, weight)
+	})
+}
+
+func prim(b *builder, g Graph, weight WeightFunc) {
+	var frontier edgeFrontier = &randomFrontier{rng: b.rng}
+	if weight != nil {
+		frontier = &weightedFrontier{weight: weight}
+	}
+
+	// add n to the maze and its edges leading out of the maze to the frontier.
+	visit := func(n Node) {
+		b.add(n)
+		for _, neighbor := range g.Neighbors(n) {
+			if !b.maze.Has(neighbor) {
+				frontier.push(Edge{A: n, B: neighbor})
+			}
+		}
+	}
+
+	for _, component := range Components(g) {
+		visit(component[b.rng.Intn(len(component))])
+		for frontier.Len() > 0 {
+			if b.stopped() {
+				return
+			}
+			e := frontier.pop()
+			if b.maze.Has(e.B) {
+				continue // edge became internal to the maze since it was pushed
+			}
+			b.carve(e.A, e.B)
+			visit(e.B)
+		}
+	}
+}
+
+// AldousBroder implements the Aldous-Broder algorithm. It performs a random
+// walk over g, adding the edge it walked along to the maze whenever the walk
+// enters a node for the first time. Like Wilson's algorithm it makes uniform
+// spanning trees. It is simpler but slower, so it is a useful reference.
+// see: http://weblog.jamisbuck.org/2011/1/17/maze-generation-aldous-broder-algorithm
+func AldousBroder(g Graph) Graph {
+	return AldousBroderRand(g, defaultRNG)
+}
+
+// AldousBroderRand is AldousBroder using rng as the source of randomness.
+func AldousBroderRand(g Graph, rng RNG) Graph {
+	maze, _ := AldousBroderContext(context.Background(), g, Options{Rand: rng})
+	return maze
+}
+
+// AldousBroderContext is AldousBroder controlled by ctx and opts.
+func AldousBroderContext(ctx context.Context, g Graph, opts Options) (Graph, error) {
+	return build(ctx, g.NodeCount(), opts, func(b *builder) {
+		aldousBroder(b, g)
+	})
+}
 
-		// create a random walk through unvisited graph
-		path := NodeSlice{n}
-		for pathCreated := false; !pathCreated; {
+func aldousBroder(b *builder, g Graph) {
+	for _, component := range Components(g) {
+		n := component[b.rng.Intn(len(component))]
+		b.add(n)
+
+		// count the nodes left to visit rather than searching g for them, so
+		// the end of a long walk costs no more per step than the beginning.
+		for remaining := len(component) - 1; remaining > 0; {
+			if b.stopped() {
+				return
+			}
 			neighbors := g.Neighbors(n)
-			n = neighbors[rand.Intn(len(neighbors))] // get random neighbor
-			path = path.Append(n)
+			next := neighbors[b.rng.Intn(len(neighbors))] // get random neighbor
+			b.emit(WalkStep, n, next)
+			if !b.maze.Has(next) {
+				b.carve(n, next)
+				remaining--
+			}
+			n = next
+		}
+	}
+}
+
+// HuntAndKill implements the hunt-and-kill algorithm. It carves a random
+// walk until it reaches a node with no unvisited neighbors, then "hunts" for
+// an unvisited node next to the maze by scanning the nodes of g in order,
+// connects it to the maze and begins walking again. The scan uses the order
+// given by g.Nodes(), so it does not depend on map iteration order.
+// see: http://weblog.jamisbuck.org/2011/1/24/maze-generation-hunt-and-kill-algorithm
+func HuntAndKill(g Graph) Graph {
+	return HuntAndKillRand(g, defaultRNG)
+}
+
+// HuntAndKillRand is HuntAndKill using rng as the source of randomness.
+func HuntAndKillRand(g Graph, rng RNG) Graph {
+	maze, _ := HuntAndKillContext(context.Background(), g, Options{Rand: rng})
+	return maze
+}
+
+// HuntAndKillContext is HuntAndKill controlled by ctx and opts.
+func HuntAndKillContext(ctx context.Context, g Graph, opts Options) (Graph, error) {
+	return build(ctx, g.NodeCount(), opts, func(b *builder) {
+		huntAndKill(b, g)
+	})
+}
+
+func huntAndKill(b *builder, g Graph) {
+	nodes := g.Nodes()
+	if len(nodes) == 0 {
+		return
+	}
 
-			// check if next is already in the path
-			if prev := path.index(n); prev != notFound && prev != len(path)-1 {
-				// it's already in the path, we have a loop.
-				// must cut loop out
-				path = path[:prev+1]
+	n := nodes[b.rng.Intn(len(nodes))]
+	b.add(n)
+
+	first := 0 // all nodes before first are in the maze
+	var candidates NodeSlice
+	for n != nil {
+		// kill: walk to random unvisited neighbors until stuck.
+		for !b.stopped() {
+			candidates = candidates[:0]
+			for _, neighbor := range g.Neighbors(n) {
+				if !b.maze.Has(neighbor) {
+					candidates = candidates.Append(neighbor)
+				}
+			}
+			if len(candidates) == 0 {
+				break
 			}
+			next := candidates[b.rng.Intn(len(candidates))]
+			b.carve(n, next)
+			n = next
+		}
 
-			pathCreated = maze.Has(n) // check if next is currently in the 'maze' (visted nodes)
+		// hunt: find the first unvisited node which borders the maze and
+		// connect it to a random one of its visited neighbors.
+		for first < len(nodes) && b.maze.Has(nodes[first]) {
+			first++
+		}
+		n = nil
+		for i := first; i < len(nodes) && n == nil; i++ {
+			if b.stopped() {
+				return
+			}
+			if b.maze.Has(nodes[i]) {
+				continue
+			}
+			candidates = candidates[:0]
+			for _, neighbor := range g.Neighbors(nodes[i]) {
+				if b.maze.Has(neighbor) {
+					candidates = candidates.Append(neighbor)
+				}
+			}
+			if len(candidates) > 0 {
+				n = nodes[i]
+				b.carve(n, candidates[b.rng.Intn(len(candidates))])
+			}
 		}
 
-		// add the path to the maze
-		for i := 0; i < len(path)-1; i++ {
-			maze.AddEdge(path[i], path[i+1])
+		// nothing borders the maze, but if nodes are left they are in
+		// another connected component. start a new walk there.
+		if n == nil && first < len(nodes) {
+			n = nodes[first]
+			b.add(n)
+		}
+	}
+}
+
+// Selector picks which node the growing tree algorithm works on next. It is
+// given the number of active nodes, which are ordered from oldest to newest,
+// and returns the index of one of them. Any random choice should use rng.
+type Selector func(active int, rng RNG) int
+
+// SelectNewest always picks the most recently added node, which makes
+// GrowingTree behave like Backtracker.
+func SelectNewest(active int, rng RNG) int { return active - 1 }
+
+// SelectOldest always picks the node that has been active the longest.
+func SelectOldest(active int, rng RNG) int { return 0 }
+
+// SelectRandom picks any active node with equal probability, which makes
+// GrowingTree behave like Prim.
+func SelectRandom(active int, rng RNG) int { return rng.Intn(active) }
+
+// SelectMix makes a Selector which uses a with probability p and b
+// otherwise. For example, SelectMix(SelectNewest, 0.75, SelectRandom)
+// picks the newest node 75% of the time and a random node the rest.
+func SelectMix(a Selector, p float64, b Selector) Selector {
+	return func(active int, rng RNG) int {
+		if rng.Float64() < p {
+			return a(active, rng)
 		}
+		return b(active, rng)
 	}
+}
+
+// GrowingTree implements the growing tree algorithm. It keeps a list of
+// active nodes, uses sel to choose one of them, and carves to a random
+// unvisited neighbor of that node, which then becomes active. Nodes without
+// unvisited neighbors are removed from the list. The texture of the maze
+// depends on sel.
+// see: http://weblog.jamisbuck.org/2011/1/27/maze-generation-growing-tree-algorithm
+func GrowingTree(g Graph, sel Selector) Graph {
+	return GrowingTreeRand(g, sel, defaultRNG)
+}
+
+// GrowingTreeRand is GrowingTree using rng as the source of randomness.
+func GrowingTreeRand(g Graph, sel Selector, rng RNG) Graph {
+	maze, _ := GrowingTreeContext(context.Background(), g, sel, Options{Rand: rng})
+	return maze
+}
+
+// GrowingTreeContext is GrowingTree controlled by ctx and opts.
+func GrowingTreeContext(ctx context.Context, g Graph, sel Selector, opts Options) (Graph, error) {
+	return build(ctx, g.NodeCount(), opts, func(b *builder) {
+		growingTree(b, g, sel)
+	})
+}
+
+func growingTree(b *builder, g Graph, sel Selector) {
+	var active, unvisited NodeSlice
+	for _, component := range Components(g) {
+		n := component[b.rng.Intn(len(component))]
+		b.add(n)
+		active = active.Append(n)
 
-	return
+		for len(active) > 0 {
+			if b.stopped() {
+				return
+			}
+			i := sel(len(active), b.rng)
+			n = active[i]
+
+			unvisited = unvisited[:0]
+			for _, neighbor := range g.Neighbors(n) {
+				if !b.maze.Has(neighbor) {
+					unvisited = unvisited.Append(neighbor)
+				}
+			}
+
+			if len(unvisited) > 0 {
+				next := unvisited[b.rng.Intn(len(unvisited))]
+				b.carve(n, next)
+				active = active.Append(next)
+				continue
+			}
+
+			// n is finished. the order of active must be kept, but removing
+			// either end (the usual case) doesn't require copying.
+			switch i {
+			case 0:
+				active[0] = nil // prevent memory leak
+				active = active[1:]
+			case len(active) - 1:
+				active, _ = active.pop()
+			default:
+				active = append(active[:i], active[i+1:]...)
+			}
+		}
+	}
 }
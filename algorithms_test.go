@@ -0,0 +1,368 @@
+package maze
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// checkPerfect fails the test if maze is not a spanning tree of g, or a
+// spanning forest if g is not connected.
+func checkPerfect(t *testing.T, g, maze Graph) {
+	t.Helper()
+
+	if g.NodeCount() != maze.NodeCount() {
+		t.Fatalf("maze has %d nodes, want %d", maze.NodeCount(), g.NodeCount())
+	}
+	for _, n := range g.Nodes() {
+		if !maze.Has(n) {
+			t.Fatalf("maze is missing node (%v)", n)
+		}
+	}
+
+	edges := Edges(maze)
+	for _, e := range edges {
+		if !g.HasEdge(e.A, e.B) {
+			t.Fatalf("maze edge (%v)-(%v) is not in g", e.A, e.B)
+		}
+	}
+
+	// a graph with n nodes, c components, and n-c edges is a forest. since
+	// maze is a subgraph of g with as many components, each of its trees
+	// spans a component of g.
+	components := len(Components(g))
+	if got := len(Components(maze)); got != components {
+		t.Fatalf("maze has %d components, want %d", got, components)
+	}
+	if want := g.NodeCount() - components; len(edges) != want {
+		t.Fatalf("maze has %d edges, want %d (it has loops)", len(edges), want)
+	}
+}
+
+// generatorTests are graphs which every generator must turn into a
+// perfect maze.
+func generatorTests() []struct {
+	name string
+	g    Graph
+} {
+	return []struct {
+		name string
+		g    Graph
+	}{
+		{name: "empty", g: NewMapGraph()},
+		{name: "single node", g: MakeGrid(1, 1, 1)},
+		{name: "10x10", g: MakeGrid(10, 10, 1)},
+		{name: "5x5x5", g: MakeGrid(5, 5, 5)},
+		{name: "custom", g: mapgraph{
+			0: NodeSlice{1, 2, 3},
+			1: NodeSlice{0, 2},
+			2: NodeSlice{0, 1, 3},
+			3: NodeSlice{0, 2},
+		}},
+		{name: "disconnected", g: constructGraph1()},
+		{name: "isolated nodes", g: mapgraph{
+			0: NodeSlice{1},
+			1: NodeSlice{0},
+			2: NodeSlice{},
+			3: NodeSlice{},
+		}},
+		{name: "grid with holes", g: maskedGrid()},
+	}
+}
+
+// maskedGrid makes a 10x10 grid with a wall of removed nodes across its
+// middle and an isolated node in its corner.
+func maskedGrid() Graph {
+	g := MakeGrid(10, 10, 1)
+	for x := 0; x < 10; x++ {
+		g.Remove(Node(ThreeToOne(x, 5, 0, 10, 10)))
+	}
+	g.Remove(Node(ThreeToOne(1, 0, 0, 10, 10)), Node(ThreeToOne(0, 1, 0, 10, 10)))
+	return g
+}
+
+func TestBacktracker(t *testing.T) {
+	for _, tt := range generatorTests() {
+		t.Run(tt.name, func(t *testing.T) {
+			checkPerfect(t, tt.g, Backtracker(tt.g))
+		})
+	}
+}
+
+func TestKruskal(t *testing.T) {
+	for _, tt := range generatorTests() {
+		t.Run(tt.name, func(t *testing.T) {
+			checkPerfect(t, tt.g, Kruskal(tt.g))
+		})
+	}
+}
+
+func TestPrim(t *testing.T) {
+	for _, tt := range generatorTests() {
+		t.Run(tt.name, func(t *testing.T) {
+			checkPerfect(t, tt.g, Prim(tt.g))
+		})
+	}
+}
+
+func TestPrimWeighted(t *testing.T) {
+	for _, tt := range generatorTests() {
+		t.Run(tt.name, func(t *testing.T) {
+			checkPerfect(t, tt.g, PrimWeighted(tt.g, RandomWeights(defaultRNG)))
+		})
+	}
+
+	// with fixed weights the maze is the minimum spanning tree.
+	g := mapgraph{
+		0: NodeSlice{1, 2, 3},
+		1: NodeSlice{0, 2},
+		2: NodeSlice{0, 1, 3},
+		3: NodeSlice{0, 2},
+	}
+	heavy := map[Edge]bool{{0, 2}: true, {2, 0}: true, {2, 3}: true, {3, 2}: true}
+	weight := func(a, b Node) float64 {
+		if heavy[Edge{a, b}] {
+			return 10
+		}
+		return 1
+	}
+	maze := PrimWeighted(g, weight)
+	for _, e := range []Edge{{0, 1}, {1, 2}, {0, 3}} {
+		if !maze.HasEdge(e.A, e.B) {
+			t.Errorf("PrimWeighted() is missing light edge (%v)-(%v): %v", e.A, e.B, maze)
+		}
+	}
+}
+
+func TestAldousBroder(t *testing.T) {
+	for _, tt := range generatorTests() {
+		t.Run(tt.name, func(t *testing.T) {
+			checkPerfect(t, tt.g, AldousBroder(tt.g))
+		})
+	}
+}
+
+// treeKey gives a string identifying a maze made of int nodes.
+func treeKey(maze Graph) string {
+	var keys []string
+	for _, e := range Edges(maze) {
+		a, b := e.A.(int), e.B.(int)
+		if a > b {
+			a, b = b, a
+		}
+		keys = append(keys, fmt.Sprintf("%d-%d", a, b))
+	}
+	sort.Strings(keys)
+	return fmt.Sprint(keys)
+}
+
+func TestLoopErasedRandomWalkContext(t *testing.T) {
+	g := MakeGrid(20, 20, 1)
+	stop := newIndexedSet(NodeSlice{399})
+	for seed := uint64(0); seed < 10; seed++ {
+		want := LoopErasedRandomWalkRand(g, 0, stop, NewPCG(seed, 0))
+		got, err := LoopErasedRandomWalkContext(context.Background(), g, 0, stop, Options{Rand: NewPCG(seed, 0)})
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Fatalf("LoopErasedRandomWalkContext() = %v, %v, want %v", got, err, want)
+		}
+	}
+
+	// without the check, an unreachable stop is only noticed when ctx is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	path, err := LoopErasedRandomWalkContext(ctx, constructGraph1(), 0, newIndexedSet(NodeSlice{-1}), Options{})
+	if path != nil || err != context.DeadlineExceeded {
+		t.Errorf("LoopErasedRandomWalkContext() = %v, %v, want nil, %v", path, err, context.DeadlineExceeded)
+	}
+
+	isolated := mapgraph{0: NodeSlice{}, 1: NodeSlice{}}
+	path, err = LoopErasedRandomWalkContext(context.Background(), isolated, 0, newIndexedSet(NodeSlice{1}), Options{})
+	if path != nil || err != nil {
+		t.Errorf("LoopErasedRandomWalkContext() = %v, %v from an isolated node, want nil, nil", path, err)
+	}
+}
+
+// TestUniformSpanningTree checks that the generators which should produce
+// uniform spanning trees do so. A 3x2 grid has 15 spanning trees, so each
+// should be made about 1/15th of the time.
+func TestUniformSpanningTree(t *testing.T) {
+	const (
+		trees  = 15
+		trials = 300 * trees
+		// chi-squared critical value for 14 degrees of freedom at p=0.001
+		critical = 36.12
+	)
+	tests := []struct {
+		name     string
+		generate func(Graph, RNG) Graph
+	}{
+		{name: "AldousBroder", generate: AldousBroderRand},
+		{name: "Wilson", generate: WilsonRand},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// one fixed source for all trials, so the result is the same
+			// every run.
+			rng := NewPCG(1, 0)
+			counts := make(map[string]int)
+			for i := 0; i < trials; i++ {
+				counts[treeKey(tt.generate(MakeGrid(3, 2, 1), rng))]++
+			}
+			if len(counts) != trees {
+				t.Fatalf("made %d distinct trees, want %d", len(counts), trees)
+			}
+
+			expected := float64(trials) / trees
+			chi2 := 0.0
+			for _, c := range counts {
+				d := float64(c) - expected
+				chi2 += d * d / expected
+			}
+			if chi2 > critical {
+				t.Errorf("chi-squared = %.2f exceeds %.2f; counts: %v", chi2, critical, counts)
+			}
+		})
+	}
+}
+
+func TestHuntAndKill(t *testing.T) {
+	for _, tt := range generatorTests() {
+		t.Run(tt.name, func(t *testing.T) {
+			checkPerfect(t, tt.g, HuntAndKill(tt.g))
+		})
+	}
+}
+
+func TestGrowingTree(t *testing.T) {
+	selectors := []struct {
+		name string
+		sel  Selector
+	}{
+		{name: "newest", sel: SelectNewest},
+		{name: "oldest", sel: SelectOldest},
+		{name: "random", sel: SelectRandom},
+		{name: "mix", sel: SelectMix(SelectNewest, 0.75, SelectRandom)},
+	}
+	for _, s := range selectors {
+		for _, tt := range generatorTests() {
+			t.Run(s.name+"/"+tt.name, func(t *testing.T) {
+				checkPerfect(t, tt.g, GrowingTree(tt.g, s.sel))
+			})
+		}
+	}
+}
+
+// TestReproducible checks that every algorithm makes the same maze when
+// given RNGs with the same seed.
+func TestReproducible(t *testing.T) {
+	grid := MakeGrid(12, 9, 2)
+	tests := []struct {
+		name     string
+		generate func(rng RNG) Graph
+	}{
+		{name: "Wilson", generate: func(rng RNG) Graph { return WilsonRand(grid, rng) }},
+		{name: "Backtracker", generate: func(rng RNG) Graph { return BacktrackerRand(grid, rng) }},
+		{name: "Kruskal", generate: func(rng RNG) Graph { return KruskalRand(grid, rng) }},
+		{name: "Prim", generate: func(rng RNG) Graph { return PrimRand(grid, rng) }},
+		{name: "PrimWeighted", generate: func(rng RNG) Graph { return PrimWeightedRand(grid, RandomWeights(rng), rng) }},
+		{name: "AldousBroder", generate: func(rng RNG) Graph { return AldousBroderRand(grid, rng) }},
+		{name: "HuntAndKill", generate: func(rng RNG) Graph { return HuntAndKillRand(grid, rng) }},
+		{name: "GrowingTree", generate: func(rng RNG) Graph {
+			return GrowingTreeRand(grid, SelectMix(SelectNewest, 0.5, SelectOldest), rng)
+		}},
+		{name: "Eller", generate: func(rng RNG) Graph {
+			maze := NewMapGraph()
+			EllerRand(12, 9, 2, func(l Layer) bool {
+				for _, e := range append(l.Edges, l.Next...) {
+					maze.AddEdge(e.A, e.B)
+				}
+				return true
+			}, rng)
+			return maze
+		}},
+		{name: "BinaryTree", generate: func(rng RNG) Graph { return BinaryTreeRand(12, 9, 2, Bias{}, rng) }},
+		{name: "Sidewinder", generate: func(rng RNG) Graph { return SidewinderRand(12, 9, 2, Bias{}, rng) }},
+		{name: "RecursiveDivision", generate: func(rng RNG) Graph { return RecursiveDivisionRand(12, 9, 2, rng) }},
+		{name: "Braid", generate: func(rng RNG) Graph {
+			maze := WilsonRand(grid, rng)
+			BraidRand(maze, grid, 0.5, rng)
+			return maze
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.generate(rand.New(rand.NewSource(1)))
+			b := tt.generate(rand.New(rand.NewSource(1)))
+			if !reflect.DeepEqual(a, b) {
+				t.Errorf("made different mazes from the same seed")
+			}
+			if c := tt.generate(rand.New(rand.NewSource(2))); reflect.DeepEqual(a, c) {
+				t.Errorf("made the same maze from different seeds")
+			}
+		})
+	}
+}
+
+func TestWilson(t *testing.T) {
+	for _, tt := range generatorTests() {
+		t.Run(tt.name, func(t *testing.T) {
+			checkPerfect(t, tt.g, Wilson(tt.g))
+		})
+	}
+}
+
+func BenchmarkWilson(b *testing.B) {
+	for _, size := range []int{64, 256, 512} {
+		g := MakeGrid(size, size, 1)
+		b.Run(fmt.Sprintf("%dx%d", size, size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				WilsonRand(g, NewPCG(uint64(i), 0))
+			}
+		})
+	}
+}
+
+func TestLoopErasedRandomWalk(t *testing.T) {
+	tests := []struct {
+		name  string
+		g     Graph
+		start Node
+		stop  NodeSet
+	}{
+		{name: "start in stop", g: MakeGrid(5, 5, 1), start: 0, stop: newIndexedSet(NodeSlice{0})},
+		{name: "corner to corner", g: MakeGrid(20, 20, 1), start: 0, stop: newIndexedSet(NodeSlice{399})},
+		{name: "to a maze", g: MakeGrid(20, 20, 2), start: 0, stop: mapgraph{799: NodeSlice{798}, 798: NodeSlice{799}}},
+		{name: "unreachable", g: constructGraph1(), start: 0, stop: newIndexedSet(NodeSlice{-1})},
+		{name: "isolated", g: mapgraph{0: NodeSlice{}, 1: NodeSlice{}}, start: 0, stop: newIndexedSet(NodeSlice{1})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := uint64(0); seed < 20; seed++ {
+				path := LoopErasedRandomWalkRand(tt.g, tt.start, tt.stop, NewPCG(seed, 0))
+				if reachable := reaches(tt.g, tt.start, tt.stop); path == nil != !reachable {
+					t.Fatalf("path = %v, but reachable = %v", path, reachable)
+				} else if !reachable {
+					continue
+				}
+				if path[0] != tt.start {
+					t.Fatalf("path starts at %v, want %v", path[0], tt.start)
+				}
+				for i, n := range path {
+					if tt.stop.Has(n) != (i == len(path)-1) {
+						t.Fatalf("path %v should reach stop only at its end", path)
+					}
+					if i > 0 && !tt.g.HasEdge(path[i-1], n) {
+						t.Fatalf("path steps from %v to %v, which are not neighbors", path[i-1], n)
+					}
+					if path.index(n) != i {
+						t.Fatalf("path %v has a loop at %v", path, n)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package maze
+
+import (
+	"container/heap"
+	"math"
+)
+
+// Heuristic estimates the length of the shortest path from n to goal. AStar
+// only finds a shortest path if the estimate is never too long.
+type Heuristic func(n, goal Node) float64
+
+// AStar finds a shortest path through maze from start to goal using the A*
+// search algorithm, guided by h. With a good heuristic it looks at far fewer
+// nodes than Solve. A nil h estimates every distance as 0, which makes AStar
+// a (slower) breadth first search. The path and errors are as for Solve.
+func AStar(maze Graph, start, goal Node, h Heuristic) (NodeSlice, error) {
+	if err := checkEnds(maze, start, goal); err != nil {
+		return nil, err
+	}
+	if h == nil {
+		h = func(n, goal Node) float64 { return 0 }
+	}
+
+	prev := map[Node]Node{start: nil}
+	cost := map[Node]int{start: 0} // length of the best path found to each node
+	open := &searchQueue{}
+	heap.Push(open, searchItem{n: start, f: h(start, goal)})
+	for open.Len() > 0 {
+		item := heap.Pop(open).(searchItem)
+		if item.g > cost[item.n] {
+			continue // a shorter path to n was found after this was pushed
+		}
+		if item.n == goal {
+			return tracePath(prev, start, goal), nil
+		}
+
+		for _, neighbor := range maze.Neighbors(item.n) {
+			g := item.g + 1
+			if c, seen := cost[neighbor]; seen && c <= g {
+				continue
+			}
+			cost[neighbor], prev[neighbor] = g, item.n
+			heap.Push(open, searchItem{n: neighbor, g: g, f: float64(g) + h(neighbor, goal), seq: open.pushed})
+		}
+	}
+	return nil, &UnreachableError{Start: start, Goal: goal}
+}
+
+// Position is implemented by nodes which have a location in space, which
+// lets the Manhattan and Euclidean heuristics estimate distances between
+// them. The Manhattan distance between neighboring nodes, the sum of their
+// distances along each axis, must be at most 1. Otherwise, as with diagonal
+// neighbors, the heuristics may overestimate and AStar may not find a
+// shortest path.
+type Position interface {
+	Position() (x, y, z float64)
+}
+
+// Manhattan is a Heuristic for nodes which implement Position. It gives the
+// sum of the distances along each axis, which is the exact length of the
+// path in a grid without walls. Nodes which don't implement Position are
+// estimated to be 0 apart.
+func Manhattan(n, goal Node) float64 {
+	d, ok := delta(n, goal)
+	if !ok {
+		return 0
+	}
+	return math.Abs(d[0]) + math.Abs(d[1]) + math.Abs(d[2])
+}
+
+// Euclidean is a Heuristic for nodes which implement Position. It gives the
+// straight line distance between the nodes. It is never longer than
+// Manhattan, so AStar looks at more nodes with it. Nodes which don't
+// implement Position are estimated to be 0 apart.
+func Euclidean(n, goal Node) float64 {
+	d, ok := delta(n, goal)
+	if !ok {
+		return 0
+	}
+	return math.Sqrt(d[0]*d[0] + d[1]*d[1] + d[2]*d[2])
+}
+
+// delta gives the difference between the positions of a and b.
+func delta(a, b Node) (d [3]float64, ok bool) {
+	pa, ok := a.(Position)
+	if !ok {
+		return d, false
+	}
+	pb, ok := b.(Position)
+	if !ok {
+		return d, false
+	}
+	ax, ay, az := pa.Position()
+	bx, by, bz := pb.Position()
+	return [3]float64{ax - bx, ay - by, az - bz}, true
+}
+
+// gridNode gives a node made by MakeGrid with the given dx and dy a
+// Position.
+type gridNode struct {
+	n      Node
+	dx, dy int
+}
+
+func (g gridNode) Position() (x, y, z float64) {
+	ix, iy, iz := gridPosition(g.n, g.dx, g.dy)
+	return float64(ix), float64(iy), float64(iz)
+}
+
+// GridManhattan is Manhattan for the nodes of MakeGrid(dx, dy, dz).
+func GridManhattan(dx, dy int) Heuristic {
+	return func(n, goal Node) float64 {
+		return Manhattan(gridNode{n, dx, dy}, gridNode{goal, dx, dy})
+	}
+}
+
+// GridEuclidean is Euclidean for the nodes of MakeGrid(dx, dy, dz).
+func GridEuclidean(dx, dy int) Heuristic {
+	return func(n, goal Node) float64 {
+		return Euclidean(gridNode{n, dx, dy}, gridNode{goal, dx, dy})
+	}
+}
+
+// searchItem is a node waiting to be looked at by AStar. g is the length of
+// the path to it, and f the estimated length of the path through it to the
+// goal.
+type searchItem struct {
+	n   Node
+	g   int
+	f   float64
+	seq int // order pushed, so that ties are broken the same way every time
+}
+
+// searchQueue pops the item with the lowest f. Ties go to the item nearest
+// the goal, then to the one pushed first. It implements heap.Interface, so
+// Push and Pop should not be called directly.
+type searchQueue struct {
+	items  []searchItem
+	pushed int
+}
+
+func (q *searchQueue) Len() int      { return len(q.items) }
+func (q *searchQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *searchQueue) Less(i, j int) bool {
+	a, b := q.items[i], q.items[j]
+	if a.f != b.f {
+		return a.f < b.f
+	}
+	if a.g != b.g {
+		return a.g > b.g
+	}
+	return a.seq < b.seq
+}
+
+func (q *searchQueue) Push(x interface{}) {
+	q.items = append(q.items, x.(searchItem))
+	q.pushed++
+}
+
+func (q *searchQueue) Pop() interface{} {
+	l := len(q.items)
+	item := q.items[l-1]
+	q.items[l-1] = searchItem{} // prevent memory leak
+	q.items = q.items[:l-1]
+	return item
+}
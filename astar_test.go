@@ -0,0 +1,112 @@
+package maze
+
+import (
+	"errors"
+	"testing"
+)
+
+// point is a node with its own coordinates.
+type point struct{ x, y int }
+
+func (p point) Position() (x, y, z float64) {
+	return float64(p.x), float64(p.y), 0
+}
+
+func TestAStar(t *testing.T) {
+	grid := MakeGrid(20, 20, 1)
+	braided := WilsonRand(grid, NewPCG(8, 0))
+	AddLoopsRand(braided, grid, 40, LoopsRandom, NewPCG(8, 0))
+	cube := MakeGrid(8, 8, 8)
+
+	points := NewMapGraph()
+	for x := 0; x < 6; x++ {
+		for y := 0; y < 6; y++ {
+			if x < 5 {
+				points.AddEdge(point{x, y}, point{x + 1, y})
+			}
+			if y < 5 && (y != 2 || x == 5) { // a wall with a gap at the right
+				points.AddEdge(point{x, y}, point{x, y + 1})
+			}
+		}
+	}
+
+	tests := []struct {
+		name        string
+		maze        Graph
+		start, goal Node
+		h           Heuristic
+	}{
+		{"nil", braided, 0, 399, nil},
+		{"grid manhattan", braided, 0, 399, GridManhattan(20, 20)},
+		{"grid euclidean", braided, 399, 0, GridEuclidean(20, 20)},
+		{"perfect", Kruskal(grid), 21, 378, GridManhattan(20, 20)},
+		{"3D", Backtracker(cube), 0, 511, GridManhattan(8, 8)},
+		{"3D braided", cube, 7, 448, GridEuclidean(8, 8)},
+		{"same node", grid, 5, 5, GridManhattan(20, 20)},
+		{"position manhattan", points, point{0, 0}, point{0, 5}, Manhattan},
+		{"position euclidean", points, point{0, 5}, point{3, 0}, Euclidean},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := AStar(tt.maze, tt.start, tt.goal, tt.h)
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			checkPath(t, tt.maze, path, tt.start, tt.goal)
+			if want := distance(tt.maze, tt.start, tt.goal) + 1; len(path) != want {
+				t.Errorf("path has %d nodes, want %d", len(path), want)
+			}
+		})
+	}
+}
+
+func TestAStar_guided(t *testing.T) {
+	// in an open grid a good heuristic leads straight to the goal.
+	grid := MakeGrid(30, 30, 30)
+	looked := 0
+	manhattan := GridManhattan(30, 30)
+	h := func(n, goal Node) float64 {
+		looked++
+		return manhattan(n, goal)
+	}
+	if _, err := AStar(grid, 0, grid.NodeCount()-1, h); err != nil {
+		t.Fatalf("err = %v", err)
+	}
+	if looked > 1000 {
+		t.Errorf("estimated %d distances in a grid of %d nodes", looked, grid.NodeCount())
+	}
+}
+
+func TestAStar_errors(t *testing.T) {
+	g := maskedGrid()
+	var missing *MissingNodeError
+	if _, err := AStar(g, 0, 1, nil); !errors.As(err, &missing) || missing.Node != 1 {
+		t.Errorf("err = %v, want *MissingNodeError for (1)", err)
+	}
+	var unreachable *UnreachableError
+	if _, err := AStar(g, 2, 99, GridManhattan(10, 10)); !errors.As(err, &unreachable) {
+		t.Errorf("err = %v, want *UnreachableError", err)
+	}
+}
+
+func TestHeuristics(t *testing.T) {
+	tests := []struct {
+		name    string
+		h       Heuristic
+		n, goal Node
+		want    float64
+	}{
+		{"grid manhattan", GridManhattan(10, 5), 0, ThreeToOne(3, 4, 2, 10, 5), 9},
+		{"grid euclidean", GridEuclidean(10, 5), ThreeToOne(3, 4, 0, 10, 5), 0, 5},
+		{"manhattan", Manhattan, point{1, 1}, point{-2, 5}, 7},
+		{"euclidean", Euclidean, point{1, 1}, point{-2, 5}, 5},
+		{"not a position", Manhattan, 3, 7, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.h(tt.n, tt.goal); got != tt.want {
+				t.Errorf("h(%v, %v) = %v, want %v", tt.n, tt.goal, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package maze
+
+import (
+	"fmt"
+)
+
+// Components splits g into its connected components, the groups of nodes
+// which are connected to each other by paths. Both the components and the
+// nodes within each are in the order given by g.Nodes(), so for a connected
+// graph the only component is the same as g.Nodes().
+func Components(g Graph) []NodeSlice {
+	nodes := g.Nodes()
+
+	// label each node with its component using a breadth first search.
+	label := make(map[Node]int, len(nodes))
+	count := 0
+	var queue NodeSlice
+	for _, n := range nodes {
+		if _, in := label[n]; in {
+			continue
+		}
+		label[n] = count
+		queue = append(queue[:0], n)
+		for len(queue) > 0 {
+			m := queue[0]
+			queue = queue[1:]
+			for _, neighbor := range g.Neighbors(m) {
+				if _, in := label[neighbor]; !in {
+					label[neighbor] = count
+					queue = queue.Append(neighbor)
+				}
+			}
+		}
+		count++
+	}
+
+	components := make([]NodeSlice, count)
+	for _, n := range nodes {
+		components[label[n]] = components[label[n]].Append(n)
+	}
+	return components
+}
+
+// DisconnectedError reports that a graph which should be connected is not.
+type DisconnectedError struct {
+	Components []NodeSlice // the connected components of the graph
+}
+
+func (e *DisconnectedError) Error() string {
+	isolated := 0
+	for _, c := range e.Components {
+		if len(c) == 1 {
+			isolated++
+		}
+	}
+	return fmt.Sprintf("maze: graph is not connected: it has %d components (%d isolated nodes)",
+		len(e.Components), isolated)
+}
+
+// CheckConnected returns a *DisconnectedError if g has more than one
+// connected component. An empty graph is considered connected.
+func CheckConnected(g Graph) error {
+	if components := Components(g); len(components) > 1 {
+		return &DisconnectedError{Components: components}
+	}
+	return nil
+}
+
+// reaches returns true if there is a path in g from start to a node in stop.
+func reaches(g Graph, start Node, stop NodeSet) bool {
+	seen := map[Node]bool{start: true}
+	queue := NodeSlice{start}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if stop.Has(n) {
+			return true
+		}
+		for _, neighbor := range g.Neighbors(n) {
+			if !seen[neighbor] {
+				seen[neighbor] = true
+				queue = queue.Append(neighbor)
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package maze
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestComponents(t *testing.T) {
+	tests := []struct {
+		name string
+		g    Graph
+		want []NodeSlice
+	}{
+		{name: "empty", g: NewMapGraph(), want: []NodeSlice{}},
+		{name: "connected", g: MakeGrid(2, 2, 1), want: []NodeSlice{{0, 1, 2, 3}}},
+		{name: "graph1", g: constructGraph1(),
+			want: []NodeSlice{{-2, -1}, {0, 1, 2, 3, 4, 5, 6, 7, 8, 9}}},
+		{name: "isolated", g: mapgraph{0: NodeSlice{2}, 1: NodeSlice{}, 2: NodeSlice{0}},
+			want: []NodeSlice{{0, 2}, {1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Components(tt.g); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Components() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckConnected(t *testing.T) {
+	tests := []struct {
+		name       string
+		g          Graph
+		components int // 0 if no error is wanted
+	}{
+		{name: "empty", g: NewMapGraph()},
+		{name: "connected", g: MakeGrid(3, 3, 3)},
+		{name: "graph1", g: constructGraph1(), components: 2},
+		{name: "masked", g: maskedGrid(), components: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckConnected(tt.g)
+			var de *DisconnectedError
+			switch {
+			case tt.components == 0 && err != nil:
+				t.Errorf("CheckConnected() = %v, want nil", err)
+			case tt.components > 0 && !errors.As(err, &de):
+				t.Errorf("CheckConnected() = %v, want *DisconnectedError", err)
+			case tt.components > 0 && len(de.Components) != tt.components:
+				t.Errorf("CheckConnected() found %d components, want %d", len(de.Components), tt.components)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package maze
+
+import (
+	"context"
+	"fmt"
+)
+
+// Constrained makes a perfect maze from g with generate, such as
+// WilsonContext, which contains every edge of required and none of the
+// edges in forbidden. The nodes joined by required edges are merged into one
+// before generate is called, and forbidden edges are removed, so any
+// generator which works on a Graph can be used. A *ConstraintError is
+// returned if there is no such maze.
+func Constrained(g, required Graph, forbidden []Edge, generate func(context.Context, Graph, Options) (Graph, error)) (Graph, error) {
+	return ConstrainedRand(g, required, forbidden, generate, defaultRNG)
+}
+
+// ConstrainedRand is Constrained using rng as the source of randomness.
+func ConstrainedRand(g, required Graph, forbidden []Edge, generate func(context.Context, Graph, Options) (Graph, error), rng RNG) (Graph, error) {
+	return ConstrainedContext(context.Background(), g, required, forbidden, generate, Options{Rand: rng})
+}
+
+// ConstrainedContext is Constrained controlled by ctx and opts. Progress and
+// events are reported for the nodes and edges of the final maze.
+func ConstrainedContext(ctx context.Context, g, required Graph, forbidden []Edge, generate func(context.Context, Graph, Options) (Graph, error), opts Options) (Graph, error) {
+	banned := make(map[Edge]bool)
+	for _, e := range forbidden {
+		banned[e] = true
+		banned[Edge{A: e.B, B: e.A}] = true
+	}
+
+	// merge the nodes joined by required edges. an edge joining nodes which
+	// are already merged would make a loop.
+	sets := NewDisjointSet()
+	sets.Add(g.Nodes()...)
+	pinned := Edges(required)
+	for _, e := range pinned {
+		switch {
+		case !g.HasEdge(e.A, e.B):
+			return nil, &ConstraintError{Edge: e, Reason: "is not in g"}
+		case banned[e]:
+			return nil, &ConstraintError{Edge: e, Reason: "is both required and forbidden"}
+		case !sets.Union(e.A, e.B):
+			return nil, &ConstraintError{Edge: e, Reason: "closes a loop of required edges"}
+		}
+	}
+
+	// q is g with each set of merged nodes replaced by one of them, and
+	// without forbidden edges. when several edges of g join the same pair
+	// of sets, one is picked at random to stand for them all.
+	q := NewMapGraph()
+	via := make(map[Edge]Edge) // edges of q to those of g
+	parallel := make(map[Edge]int)
+	for _, n := range g.Nodes() {
+		q.Add(sets.Find(n))
+	}
+	rng := opts.rand()
+	for _, e := range Edges(g) {
+		a, b := sets.Find(e.A), sets.Find(e.B)
+		if a == b || banned[e] {
+			continue
+		}
+		key := Edge{A: a, B: b}
+		if _, in := via[Edge{A: b, B: a}]; in {
+			key = Edge{A: b, B: a}
+		}
+		parallel[key]++
+		if parallel[key] == 1 {
+			q.AddEdge(a, b)
+		}
+		if rng.Intn(parallel[key]) == 0 {
+			via[key] = e
+		}
+	}
+	if got, want := len(Components(q)), len(Components(g)); got != want {
+		return nil, &ConstraintError{Reason: fmt.Sprintf("forbidden edges split g into %d parts, want %d", got, want)}
+	}
+
+	return build(ctx, g.NodeCount(), opts, func(b *builder) {
+		for _, e := range pinned {
+			b.carve(e.A, e.B)
+		}
+
+		maze, err := generate(b.ctx, q, Options{Rand: b.rng})
+		if err != nil {
+			b.err = err
+			return
+		}
+		for _, e := range Edges(maze) {
+			if b.stopped() {
+				return
+			}
+			ge, in := via[e]
+			if !in {
+				ge = via[Edge{A: e.B, B: e.A}]
+			}
+			b.carve(ge.A, ge.B)
+		}
+		b.add(g.Nodes()...) // nodes without edges
+	})
+}
+
+// ConstraintError is returned when no maze has all of the required edges and
+// none of the forbidden ones.
+type ConstraintError struct {
+	Edge   Edge // the edge which can't be used, if any
+	Reason string
+}
+
+func (e *ConstraintError) Error() string {
+	if e.Edge == (Edge{}) {
+		return "maze: " + e.Reason
+	}
+	return fmt.Sprintf("maze: required edge (%v)-(%v) %s", e.Edge.A, e.Edge.B, e.Reason)
+}
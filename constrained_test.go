@@ -0,0 +1,94 @@
+package maze
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestConstrained(t *testing.T) {
+	// a corridor along the top of a 6x6 grid, and a wall down the middle
+	// below it with a single gap at the bottom.
+	required := NewMapGraph()
+	for x := 0; x < 5; x++ {
+		required.AddEdge(x, x+1)
+	}
+	var forbidden []Edge
+	for y := 1; y < 5; y++ {
+		forbidden = append(forbidden, Edge{A: y*6 + 2, B: y*6 + 3})
+	}
+
+	generators := []struct {
+		name     string
+		generate func(context.Context, Graph, Options) (Graph, error)
+	}{
+		{"Wilson", WilsonContext},
+		{"Backtracker", BacktrackerContext},
+		{"Kruskal", KruskalContext},
+		{"HuntAndKill", HuntAndKillContext},
+	}
+	for _, tt := range generators {
+		t.Run(tt.name, func(t *testing.T) {
+			g := MakeGrid(6, 6, 1)
+			for seed := uint64(0); seed < 20; seed++ {
+				maze, err := ConstrainedRand(g, required, forbidden, tt.generate, NewPCG(seed, 0))
+				if err != nil {
+					t.Fatalf("err = %v", err)
+				}
+				checkPerfect(t, g, maze)
+				for _, e := range Edges(required) {
+					if !maze.HasEdge(e.A, e.B) {
+						t.Fatalf("maze is missing required edge %v", e)
+					}
+				}
+				for _, e := range forbidden {
+					if maze.HasEdge(e.A, e.B) {
+						t.Fatalf("maze has forbidden edge %v", e)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestConstrained_disconnected(t *testing.T) {
+	g := maskedGrid()
+	maze, err := ConstrainedRand(g, NewMapGraph(), nil, WilsonContext, NewPCG(1, 0))
+	if err != nil {
+		t.Fatalf("err = %v", err)
+	}
+	checkPerfect(t, g, maze)
+}
+
+func TestConstrained_errors(t *testing.T) {
+	g := MakeGrid(3, 3, 1)
+	square := NewMapGraph()
+	square.AddEdge(0, 1)
+	square.AddEdge(1, 4)
+	square.AddEdge(4, 3)
+	square.AddEdge(3, 0)
+	pair := NewMapGraph()
+	pair.AddEdge(0, 1)
+	diagonal := NewMapGraph()
+	diagonal.AddEdge(0, 4)
+
+	tests := []struct {
+		name      string
+		required  Graph
+		forbidden []Edge
+	}{
+		{"loop", square, nil},
+		{"required and forbidden", pair, []Edge{{A: 1, B: 0}}},
+		{"not in g", diagonal, nil},
+		{"corner cut off", NewMapGraph(), []Edge{{A: 0, B: 1}, {A: 3, B: 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maze, err := Constrained(g, tt.required, tt.forbidden, WilsonContext)
+			var ce *ConstraintError
+			if maze != nil || !errors.As(err, &ce) {
+				t.Errorf("Constrained() = %v, %v, want *ConstraintError", maze, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package maze
+
+// DisjointSet is a union-find structure which partitions nodes into
+// disjoint sets. It is suitable for tracking which parts of a maze are
+// already connected.
+type DisjointSet struct {
+	parent map[Node]Node
+	size   map[Node]int // size of each set, keyed by the set's root
+	count  int          // number of disjoint sets
+}
+
+// NewDisjointSet makes an empty DisjointSet.
+func NewDisjointSet() *DisjointSet {
+	return &DisjointSet{
+		parent: make(map[Node]Node),
+		size:   make(map[Node]int),
+	}
+}
+
+// Has returns true if n has been added to the structure.
+func (s *DisjointSet) Has(n Node) bool {
+	_, in := s.parent[n]
+	return in
+}
+
+// Add puts each node into its own set if it is not already in the
+// structure.
+func (s *DisjointSet) Add(nodes ...Node) {
+	for _, n := range nodes {
+		if !s.Has(n) {
+			s.parent[n] = n
+			s.size[n] = 1
+			s.count++
+		}
+	}
+}
+
+// Find returns the representative node of the set containing n. The node
+// is added to the structure if not already present.
+func (s *DisjointSet) Find(n Node) Node {
+	s.Add(n)
+	for s.parent[n] != n {
+		// path halving: point n at its grandparent as we go.
+		s.parent[n] = s.parent[s.parent[n]]
+		n = s.parent[n]
+	}
+	return n
+}
+
+// Union merges the sets containing a and b. It returns false if a and b
+// were already in the same set.
+func (s *DisjointSet) Union(a, b Node) bool {
+	a, b = s.Find(a), s.Find(b)
+	if a == b {
+		return false
+	}
+
+	// attach the smaller tree to the root of the larger.
+	if s.size[a] < s.size[b] {
+		a, b = b, a
+	}
+	s.parent[b] = a
+	s.size[a] += s.size[b]
+	delete(s.size, b)
+	s.count--
+	return true
+}
+
+// Connected returns true if a and b are in the same set.
+func (s *DisjointSet) Connected(a, b Node) bool {
+	return s.Find(a) == s.Find(b)
+}
+
+// SetSize returns the number of nodes in the set containing n.
+func (s *DisjointSet) SetSize(n Node) int {
+	return s.size[s.Find(n)]
+}
+
+// Count returns the number of disjoint sets.
+func (s *DisjointSet) Count() int {
+	return s.count
+}
@@ -0,0 +1,79 @@
+package maze
+
+import (
+	"testing"
+)
+
+func TestDisjointSet(t *testing.T) {
+	type op struct {
+		a, b Node
+	}
+	tests := []struct {
+		name      string
+		add       NodeSlice
+		unions    []op
+		merged    []bool // result of each union
+		connected []op
+		apart     []op
+		count     int
+	}{
+		{name: "empty", count: 0},
+		{name: "singletons", add: NodeSlice{0, 1, 2}, count: 3,
+			apart: []op{{0, 1}, {1, 2}}},
+		{name: "chain", add: NodeSlice{0, 1, 2, 3},
+			unions:    []op{{0, 1}, {1, 2}, {2, 0}},
+			merged:    []bool{true, true, false},
+			connected: []op{{0, 2}, {2, 1}},
+			apart:     []op{{0, 3}},
+			count:     2},
+		{name: "union adds", unions: []op{{"a", "b"}, {"c", "d"}, {"b", "d"}},
+			merged:    []bool{true, true, true},
+			connected: []op{{"a", "c"}},
+			count:     1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDisjointSet()
+			s.Add(tt.add...)
+			for i, u := range tt.unions {
+				if got := s.Union(u.a, u.b); got != tt.merged[i] {
+					t.Errorf("DisjointSet.Union(%v, %v) = %v, want %v", u.a, u.b, got, tt.merged[i])
+				}
+			}
+			for _, c := range tt.connected {
+				if !s.Connected(c.a, c.b) {
+					t.Errorf("DisjointSet.Connected(%v, %v) = false, want true", c.a, c.b)
+				}
+			}
+			for _, c := range tt.apart {
+				if s.Connected(c.a, c.b) {
+					t.Errorf("DisjointSet.Connected(%v, %v) = true, want false", c.a, c.b)
+				}
+			}
+			if got := s.Count(); got != tt.count {
+				t.Errorf("DisjointSet.Count() = %v, want %v", got, tt.count)
+			}
+		})
+	}
+}
+
+func TestDisjointSet_SetSize(t *testing.T) {
+	s := NewDisjointSet()
+	s.Union(0, 1)
+	s.Union(2, 3)
+	s.Union(3, 1)
+	s.Add(4)
+	tests := []struct {
+		n    Node
+		want int
+	}{
+		{n: 0, want: 4},
+		{n: 3, want: 4},
+		{n: 4, want: 1},
+	}
+	for _, tt := range tests {
+		if got := s.SetSize(tt.n); got != tt.want {
+			t.Errorf("DisjointSet.SetSize(%v) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
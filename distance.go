@@ -0,0 +1,92 @@
+package maze
+
+import (
+	"container/heap"
+)
+
+// DistanceMap gives the length of the shortest path from the nearest of
+// sources to each node of maze which can be reached from one of them, found
+// with a single breadth first search. Sources have distance 0. Sources which
+// are not in maze are ignored.
+func DistanceMap(maze Graph, sources ...Node) map[Node]int {
+	dist := make(map[Node]int)
+	var queue NodeSlice
+	for _, s := range sources {
+		if _, in := dist[s]; !in && maze.Has(s) {
+			dist[s] = 0
+			queue = queue.Append(s)
+		}
+	}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, neighbor := range maze.Neighbors(n) {
+			if _, in := dist[neighbor]; !in {
+				dist[neighbor] = dist[n] + 1
+				queue = queue.Append(neighbor)
+			}
+		}
+	}
+	return dist
+}
+
+// WeightedDistanceMap is DistanceMap where the length of a path is the sum of
+// the weights of its edges, found with Dijkstra's algorithm. The weights must
+// not be negative.
+func WeightedDistanceMap(maze Graph, weight WeightFunc, sources ...Node) map[Node]float64 {
+	dist := make(map[Node]float64)
+	done := make(map[Node]bool)
+	queue := &distanceQueue{}
+	for _, s := range sources {
+		if _, in := dist[s]; !in && maze.Has(s) {
+			dist[s] = 0
+			heap.Push(queue, distanceItem{n: s})
+		}
+	}
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(distanceItem)
+		if done[item.n] {
+			continue // already reached by a shorter path
+		}
+		done[item.n] = true
+
+		for _, neighbor := range maze.Neighbors(item.n) {
+			d := item.d + weight(item.n, neighbor)
+			if old, in := dist[neighbor]; !in || d < old {
+				dist[neighbor] = d
+				heap.Push(queue, distanceItem{n: neighbor, d: d})
+			}
+		}
+	}
+	return dist
+}
+
+// distanceItem is a node waiting to be looked at by WeightedDistanceMap, and
+// the length of the path found to it.
+type distanceItem struct {
+	n Node
+	d float64
+}
+
+// distanceQueue pops the item with the lowest d. It implements
+// heap.Interface, so Push and Pop should not be called directly.
+type distanceQueue []distanceItem
+
+func (q distanceQueue) Len() int           { return len(q) }
+func (q distanceQueue) Less(i, j int) bool { return q[i].d < q[j].d }
+func (q distanceQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *distanceQueue) Push(x interface{}) {
+	*q = append(*q, x.(distanceItem))
+}
+
+func (q *distanceQueue) Pop() interface{} {
+	old := *q
+	l := len(old)
+	item := old[l-1]
+	old[l-1] = distanceItem{} // prevent memory leak
+	*q = old[:l-1]
+	return item
+}
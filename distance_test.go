@@ -0,0 +1,83 @@
+package maze
+
+import (
+	"testing"
+)
+
+func TestDistanceMap(t *testing.T) {
+	grid := MakeGrid(12, 9, 1)
+	maze := WilsonRand(grid, NewPCG(6, 0))
+	AddLoopsRand(maze, grid, 15, LoopsLong, NewPCG(6, 0))
+
+	tests := []struct {
+		name    string
+		maze    Graph
+		sources NodeSlice
+	}{
+		{"one source", maze, NodeSlice{0}},
+		{"two sources", maze, NodeSlice{0, 107}},
+		{"repeated source", maze, NodeSlice{50, 50}},
+		{"missing source", maze, NodeSlice{-3, 7}},
+		{"disconnected", Backtracker(maskedGrid()), NodeSlice{2, 99}},
+		{"no sources", maze, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DistanceMap(tt.maze, tt.sources...)
+			weighted := WeightedDistanceMap(tt.maze, func(a, b Node) float64 { return 1 }, tt.sources...)
+
+			for _, n := range tt.maze.Nodes() {
+				want := -1
+				for _, s := range tt.sources {
+					if !tt.maze.Has(s) {
+						continue
+					}
+					if d := distance(tt.maze, s, n); d != -1 && (want == -1 || d < want) {
+						want = d
+					}
+				}
+
+				d, in := got[n]
+				w, win := weighted[n]
+				switch {
+				case want == -1 && (in || win):
+					t.Errorf("(%v) can't be reached but has distance %d, %v", n, d, w)
+				case want != -1 && (d != want || w != float64(want)):
+					t.Errorf("distance to (%v) = %d, %v, want %d", n, d, w, want)
+				}
+			}
+			if len(got) != len(weighted) {
+				t.Errorf("maps have %d and %d nodes", len(got), len(weighted))
+			}
+		})
+	}
+}
+
+func TestWeightedDistanceMap(t *testing.T) {
+	// a square where going around the long way is cheaper.
+	g := NewMapGraph()
+	g.AddEdge("a", "b")
+	g.AddEdge("b", "c")
+	g.AddEdge("c", "d")
+	g.AddEdge("d", "a")
+	weights := map[Edge]float64{
+		{A: "a", B: "b"}: 10,
+		{A: "b", B: "c"}: 1,
+		{A: "c", B: "d"}: 2,
+		{A: "d", B: "a"}: 3,
+	}
+	weight := func(a, b Node) float64 {
+		if w, in := weights[Edge{A: a, B: b}]; in {
+			return w
+		}
+		return weights[Edge{A: b, B: a}]
+	}
+
+	got := WeightedDistanceMap(g, weight, "a")
+	want := map[Node]float64{"a": 0, "b": 6, "c": 5, "d": 3}
+	for n, w := range want {
+		if got[n] != w {
+			t.Errorf("distance to (%v) = %v, want %v", n, got[n], w)
+		}
+	}
+}
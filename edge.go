@@ -0,0 +1,22 @@
+package maze
+
+// Edge is an undirected edge connecting nodes A and B.
+type Edge struct {
+	A, B Node
+}
+
+// Edges returns every edge in g. Since the edges are undirected, each
+// is listed only once.
+func Edges(g Graph) []Edge {
+	edges := make([]Edge, 0, g.NodeCount())
+	done := make(map[Node]bool, g.NodeCount())
+	for _, a := range g.Nodes() {
+		for _, b := range g.Neighbors(a) {
+			if !done[b] {
+				edges = append(edges, Edge{A: a, B: b})
+			}
+		}
+		done[a] = true
+	}
+	return edges
+}
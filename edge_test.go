@@ -0,0 +1,36 @@
+package maze
+
+import (
+	"testing"
+)
+
+func TestEdges(t *testing.T) {
+	tests := []struct {
+		name string
+		g    Graph
+		want int
+	}{
+		{name: "empty", g: NewMapGraph(), want: 0},
+		{name: "isolated", g: mapgraph{0: NodeSlice{}}, want: 0},
+		{name: "3x3x3", g: MakeGrid(3, 3, 3), want: 54},
+		{name: "graph1", g: constructGraph1(), want: 17},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Edges(tt.g)
+			if len(got) != tt.want {
+				t.Errorf("len(Edges()) = %v, want %v", len(got), tt.want)
+			}
+			seen := make(map[Edge]bool)
+			for _, e := range got {
+				if !tt.g.HasEdge(e.A, e.B) {
+					t.Errorf("Edges() returned (%v)-(%v) which is not in g", e.A, e.B)
+				}
+				if seen[e] || seen[Edge{A: e.B, B: e.A}] {
+					t.Errorf("Edges() returned (%v)-(%v) twice", e.A, e.B)
+				}
+				seen[e] = true
+			}
+		})
+	}
+}
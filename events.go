@@ -0,0 +1,100 @@
+package maze
+
+import (
+	"context"
+)
+
+// EventKind identifies what happened in an Event.
+type EventKind int
+
+const (
+	// NodeVisited means node A was added to the maze.
+	NodeVisited EventKind = iota
+	// EdgeCarved means a passage between A and B was added to the maze.
+	EdgeCarved
+	// EdgeRemoved means the passage between A and B was removed from the
+	// maze. Only RecursiveDivision removes passages.
+	EdgeRemoved
+	// WalkStep means a random walk moved from A to B. The maze is not
+	// changed.
+	WalkStep
+	// LoopErased means a random walk returned to A, and the loop it made
+	// since it was last at A was erased. The maze is not changed.
+	LoopErased
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case NodeVisited:
+		return "node visited"
+	case EdgeCarved:
+		return "edge carved"
+	case EdgeRemoved:
+		return "edge removed"
+	case WalkStep:
+		return "walk step"
+	case LoopErased:
+		return "loop erased"
+	}
+	return "unknown event"
+}
+
+// Event is one step in making a maze, as passed to Options.Observe. B is nil
+// for events involving only one node.
+type Event struct {
+	Kind EventKind
+	A, B Node
+}
+
+// Stream calls generate in a new goroutine and returns a channel of the
+// events it makes. generate should pass ctx and opts (to which Stream adds
+// an Observe func) to one of the Context generators. Since each event must
+// be received before generation continues, the maze is made only as fast as
+// the events are consumed. The channel is closed when generate returns. If
+// the events are not all received, ctx must be canceled so that generation
+// can finish.
+//
+// wait returns the error from generate, such as a *BudgetError or ctx.Err(),
+// which tells a finished maze from one that stopped early. It blocks until
+// generate returns, so it should be called after the channel is closed.
+func Stream(ctx context.Context, opts Options, generate func(context.Context, Options) error) (events <-chan Event, wait func() error) {
+	ch := make(chan Event)
+	observe := opts.Observe
+	opts.Observe = func(e Event) {
+		if observe != nil {
+			observe(e)
+		}
+		select {
+		case ch <- e:
+		case <-ctx.Done():
+		}
+	}
+
+	var err error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer close(ch)
+		err = generate(ctx, opts)
+	}()
+	return ch, func() error {
+		<-done
+		return err
+	}
+}
+
+// Replay rebuilds a maze from the events made while generating it.
+func Replay(events []Event) Graph {
+	maze := NewMapGraph()
+	for _, e := range events {
+		switch e.Kind {
+		case NodeVisited:
+			maze.Add(e.A)
+		case EdgeCarved:
+			maze.AddEdge(e.A, e.B)
+		case EdgeRemoved:
+			maze.RemoveEdge(e.A, e.B)
+		}
+	}
+	return maze
+}
@@ -0,0 +1,104 @@
+package maze
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestReplay(t *testing.T) {
+	for _, tt := range contextGenerators {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := tt.generate(context.Background(), 6, 5, 2, Options{Rand: NewPCG(7, 0)})
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+
+			var events []Event
+			opts := Options{Rand: NewPCG(7, 0)}
+			stream, wait := Stream(context.Background(), opts, func(ctx context.Context, opts Options) error {
+				_, err := tt.generate(ctx, 6, 5, 2, opts)
+				return err
+			})
+			for e := range stream {
+				events = append(events, e)
+			}
+			if err := wait(); err != nil {
+				t.Fatalf("wait() = %v", err)
+			}
+
+			got := Replay(events)
+			if treeKey(got) != treeKey(want) || !reflect.DeepEqual(got.Nodes(), want.Nodes()) {
+				t.Errorf("Replay() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestStream_kinds(t *testing.T) {
+	count := make(map[EventKind]int)
+	stream, _ := Stream(context.Background(), Options{Rand: NewPCG(1, 0)}, func(ctx context.Context, opts Options) error {
+		_, err := WilsonContext(ctx, MakeGrid(10, 10, 1), opts)
+		return err
+	})
+	for e := range stream {
+		count[e.Kind]++
+	}
+
+	if count[NodeVisited] != 100 || count[EdgeCarved] != 99 {
+		t.Errorf("%d nodes visited and %d edges carved, want 100 and 99", count[NodeVisited], count[EdgeCarved])
+	}
+	if count[WalkStep] < 99 || count[LoopErased] == 0 {
+		t.Errorf("%d walk steps and %d loops erased", count[WalkStep], count[LoopErased])
+	}
+	if count[EdgeRemoved] != 0 {
+		t.Errorf("%d edges removed, want 0", count[EdgeRemoved])
+	}
+}
+
+func TestStream_lazy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var made int64
+	stream, wait := Stream(ctx, Options{}, func(ctx context.Context, opts Options) error {
+		observe := opts.Observe
+		opts.Observe = func(e Event) {
+			atomic.AddInt64(&made, 1)
+			observe(e)
+		}
+		_, err := BacktrackerContext(ctx, MakeGrid(100, 100, 1), opts)
+		return err
+	})
+
+	for i := 1; i <= 10; i++ {
+		<-stream
+		// the generator may have made the next event, but can't send it.
+		if n := atomic.LoadInt64(&made); n > int64(i+1) {
+			t.Fatalf("%d events made after %d were received", n, i)
+		}
+	}
+
+	cancel()
+	for range stream {
+	}
+	if err := wait(); err != context.Canceled {
+		t.Errorf("wait() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestStream_error(t *testing.T) {
+	stream, wait := Stream(context.Background(), Options{MaxNodes: 10}, func(ctx context.Context, opts Options) error {
+		_, err := WilsonContext(ctx, MakeGrid(10, 10, 1), opts)
+		return err
+	})
+	for e := range stream {
+		t.Errorf("got event %v over the budget", e)
+	}
+	var be *BudgetError
+	if err := wait(); !errors.As(err, &be) {
+		t.Errorf("wait() = %v, want *BudgetError", err)
+	}
+}
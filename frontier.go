@@ -0,0 +1,79 @@
+package maze
+
+import (
+	"container/heap"
+)
+
+// edgeFrontier is a collection of edges leading out of a partially built
+// maze. The order edges are popped depends on the implementation.
+type edgeFrontier interface {
+	push(Edge)
+	pop() Edge
+	Len() int
+}
+
+// randomFrontier pops edges uniformly at random.
+type randomFrontier struct {
+	edges []Edge
+	rng   RNG
+}
+
+func (f *randomFrontier) push(e Edge) {
+	f.edges = append(f.edges, e)
+}
+
+func (f *randomFrontier) pop() Edge {
+	i, l := f.rng.Intn(len(f.edges)), len(f.edges)
+	e := f.edges[i]
+	f.edges[i] = f.edges[l-1] // overwrite i with end
+	f.edges = f.edges[:l-1]
+	return e
+}
+
+func (f *randomFrontier) Len() int {
+	return len(f.edges)
+}
+
+// weightedFrontier pops the edge with the lowest weight. It implements
+// heap.Interface, so Push and Pop should not be called directly.
+type weightedFrontier struct {
+	weight  WeightFunc
+	edges   []Edge
+	weights []float64
+}
+
+func (f *weightedFrontier) push(e Edge) {
+	heap.Push(f, weighted{e, f.weight(e.A, e.B)})
+}
+
+func (f *weightedFrontier) pop() Edge {
+	return heap.Pop(f).(weighted).Edge
+}
+
+// weighted is an Edge and its weight, as passed to heap.Push.
+type weighted struct {
+	Edge
+	w float64
+}
+
+func (f *weightedFrontier) Len() int           { return len(f.edges) }
+func (f *weightedFrontier) Less(i, j int) bool { return f.weights[i] < f.weights[j] }
+
+func (f *weightedFrontier) Swap(i, j int) {
+	f.edges[i], f.edges[j] = f.edges[j], f.edges[i]
+	f.weights[i], f.weights[j] = f.weights[j], f.weights[i]
+}
+
+func (f *weightedFrontier) Push(x interface{}) {
+	e := x.(weighted)
+	f.edges = append(f.edges, e.Edge)
+	f.weights = append(f.weights, e.w)
+}
+
+func (f *weightedFrontier) Pop() interface{} {
+	l := len(f.edges)
+	e := weighted{f.edges[l-1], f.weights[l-1]}
+	f.edges[l-1] = Edge{} // prevent memory leak
+	f.edges, f.weights = f.edges[:l-1], f.weights[:l-1]
+	return e
+}
@@ -1,40 +1,66 @@
 package maze
 
+import (
+	"context"
+)
+
 // MakeGrid generates a Graph representing a regular "square" grid where
 // each node has at most 6 neighbors. dx, dy, and dz should be in [1,1024].
 func MakeGrid(dx, dy, dz int) Graph {
+	g, _ := MakeGridContext(context.Background(), dx, dy, dz, Options{})
+	return g
+}
+
+// MakeGridContext is MakeGrid controlled by ctx and opts. Since a grid can
+// have over a billion nodes, setting opts.MaxNodes is recommended when the
+// dimensions come from elsewhere.
+func MakeGridContext(ctx context.Context, dx, dy, dz int, opts Options) (Graph, error) {
 	max := 1024
 	dx = clamp(dx, 1, max)
 	dy = clamp(dy, 1, max)
 	dz = clamp(dz, 1, max)
 
-	g := NewMapGraph()
+	return build(ctx, dx*dy*dz, opts, func(b *builder) {
+		makeGrid(b, dx, dy, dz)
+	})
+}
 
+// makeGrid adds the nodes and edges of a dx by dy by dz grid to b's maze.
+func makeGrid(b *builder, dx, dy, dz int) {
 	for z := 0; z < dz; z++ {
 		for y := 0; y < dy; y++ {
+			if b.stopped() {
+				return
+			}
 			for x := 0; x < dx; x++ {
 				n := Node(ThreeToOne(x, y, z, dx, dy))
+				b.add(n) // carving alone misses a 1x1x1 grid, which has no edges
 				if x > 0 {
-					g.AddEdge(n, Node(ThreeToOne(x-1, y, z, dx, dy)))
+					b.carve(n, Node(ThreeToOne(x-1, y, z, dx, dy)))
 				}
 				if x < dx-1 {
-					g.AddEdge(n, Node(ThreeToOne(x+1, y, z, dx, dy)))
+					b.carve(n, Node(ThreeToOne(x+1, y, z, dx, dy)))
 				}
 				if y > 0 {
-					g.AddEdge(n, Node(ThreeToOne(x, y-1, z, dx, dy)))
+					b.carve(n, Node(ThreeToOne(x, y-1, z, dx, dy)))
 				}
 				if y < dy-1 {
-					g.AddEdge(n, Node(ThreeToOne(x, y+1, z, dx, dy)))
+					b.carve(n, Node(ThreeToOne(x, y+1, z, dx, dy)))
 				}
 				if z > 0 {
-					g.AddEdge(n, Node(ThreeToOne(x, y, z-1, dx, dy)))
+					b.carve(n, Node(ThreeToOne(x, y, z-1, dx, dy)))
 				}
 				if z < dz-1 {
-					g.AddEdge(n, Node(ThreeToOne(x, y, z+1, dx, dy)))
+					b.carve(n, Node(ThreeToOne(x, y, z+1, dx, dy)))
 				}
 			}
 		}
 	}
+}
 
-	return g
+// gridPosition gives the x, y, z position of a node made by MakeGrid with
+// the given dx and dy.
+func gridPosition(n Node, dx, dy int) (x, y, z int) {
+	i := n.(int)
+	return i % dx, i / dx % dy, i / (dx * dy)
 }
@@ -0,0 +1,413 @@
+package maze
+
+import (
+	"context"
+)
+
+// The algorithms in this file work directly with the x, y, z coordinates of
+// the nodes in a grid like the one made by MakeGrid().
+
+const (
+	ellerJoin = 0.5 // chance of joining neighboring cells within a layer
+	ellerDown = 0.5 // chance of each extra passage to the next layer
+)
+
+// Layer is one dx by dy cross-section of a maze made by Eller(). Its nodes
+// are numbered the same as the nodes at z=Z in MakeGrid(dx, dy, dz), so a
+// 2D maze made with dy=1 is streamed one row at a time.
+type Layer struct {
+	Z     int
+	Edges []Edge // passages between nodes in the layer
+	Next  []Edge // passages from this layer to layer Z+1
+}
+
+// Eller implements Eller's algorithm, which makes a maze one layer at a
+// time and passes each finished layer to emit. Only the state of a single
+// layer is kept, so the maze can be arbitrarily long. If dz is less than 1
+// the maze is endless and generation continues until emit returns false.
+// dx and dy should be in [1,1024].
+// see: http://weblog.jamisbuck.org/2010/12/29/maze-generation-eller-s-algorithm
+func Eller(dx, dy, dz int, emit func(Layer) bool) {
+	EllerRand(dx, dy, dz, emit, defaultRNG)
+}
+
+// EllerRand is Eller using rng as the source of randomness.
+func EllerRand(dx, dy, dz int, emit func(Layer) bool, rng RNG) {
+	EllerContext(context.Background(), dx, dy, dz, emit, Options{Rand: rng})
+}
+
+// EllerContext is Eller controlled by ctx and opts. It returns nil if the
+// maze is finished or emit returns false, and otherwise the reason it
+// stopped. For an endless maze, progress is reported with a total of 0 and
+// a *BudgetError is returned once the maze passes opts.MaxNodes.
+func EllerContext(ctx context.Context, dx, dy, dz int, emit func(Layer) bool, opts Options) error {
+	max := 1024
+	dx = clamp(dx, 1, max)
+	dy = clamp(dy, 1, max)
+	cells := dx * dy
+	rng := opts.rand()
+
+	total := 0
+	if dz > 0 {
+		total = cells * dz
+	}
+	if opts.MaxNodes > 0 && total > opts.MaxNodes {
+		return &BudgetError{Nodes: total, MaxNodes: opts.MaxNodes}
+	}
+
+	node := func(i, z int) Node {
+		return Node(ThreeToOne(i%dx, i/dx, z, dx, dy))
+	}
+
+	// pairs of neighboring cells within a layer
+	var pairs [][2]int
+	for i := 0; i < cells; i++ {
+		if i%dx < dx-1 {
+			pairs = append(pairs, [2]int{i, i + 1})
+		}
+		if i/dx < dy-1 {
+			pairs = append(pairs, [2]int{i, i + dx})
+		}
+	}
+
+	// the set of each cell in the current layer. cells in the same set are
+	// connected by passages in this or earlier layers.
+	sets := make([]int, cells)
+	fresh := 0 // next unused set
+	for i := range sets {
+		sets[i] = fresh
+		fresh++
+	}
+
+	var down []Edge // passages from the previous layer
+	for z := 0; dz < 1 || z < dz; z++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		added := (z + 1) * cells
+		if opts.MaxNodes > 0 && added > opts.MaxNodes {
+			return &BudgetError{Nodes: added, MaxNodes: opts.MaxNodes}
+		}
+
+		last := z == dz-1
+		layer := Layer{Z: z}
+
+		// randomly join neighboring cells which are in different sets. in
+		// the last layer all sets must be joined.
+		joined := NewDisjointSet()
+		shuffle(rng, len(pairs), func(i, j int) {
+			pairs[i], pairs[j] = pairs[j], pairs[i]
+		})
+		for _, p := range pairs {
+			if (last || rng.Float64() < ellerJoin) && joined.Union(sets[p[0]], sets[p[1]]) {
+				layer.Edges = append(layer.Edges, Edge{A: node(p[0], z), B: node(p[1], z)})
+			}
+		}
+		for i := range sets {
+			sets[i] = joined.Find(sets[i]).(int)
+		}
+
+		if !last {
+			// each set continues into the next layer at least once. cells
+			// which don't continue start new sets.
+			members := make(map[int][]int)
+			var order []int // sets in order of appearance
+			for i, s := range sets {
+				if _, in := members[s]; !in {
+					order = append(order, s)
+				}
+				members[s] = append(members[s], i)
+			}
+
+			next := make([]int, cells)
+			for i := range next {
+				next[i] = fresh
+				fresh++
+			}
+			for _, s := range order {
+				m := members[s]
+				shuffle(rng, len(m), func(i, j int) {
+					m[i], m[j] = m[j], m[i]
+				})
+				for k, i := range m {
+					if k == 0 || rng.Float64() < ellerDown {
+						next[i] = s
+						layer.Next = append(layer.Next, Edge{A: node(i, z), B: node(i, z+1)})
+					}
+				}
+			}
+			sets = next
+		}
+
+		if opts.Observe != nil {
+			// passages down from the previous layer are carved once the
+			// nodes they lead to are visited.
+			for i := 0; i < cells; i++ {
+				opts.Observe(Event{Kind: NodeVisited, A: node(i, z)})
+			}
+			for _, e := range down {
+				opts.Observe(Event{Kind: EdgeCarved, A: e.A, B: e.B})
+			}
+			for _, e := range layer.Edges {
+				opts.Observe(Event{Kind: EdgeCarved, A: e.A, B: e.B})
+			}
+			down = layer.Next
+		}
+		if opts.Progress != nil {
+			opts.Progress(added, total)
+		}
+		if !emit(layer) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// Bias gives the direction along each axis in which BinaryTree and
+// Sidewinder carve passages. A negative value carves toward smaller
+// coordinates, and zero or a positive value toward larger coordinates.
+type Bias struct {
+	X, Y, Z int
+}
+
+// direction gives the step, -1 or 1, for an axis bias.
+func direction(bias int) int {
+	if bias < 0 {
+		return -1
+	}
+	return 1
+}
+
+// inGrid returns true if 0 <= i < size.
+func inGrid(i, size int) bool {
+	return 0 <= i && i < size
+}
+
+// BinaryTree implements the binary tree algorithm on a dx by dy by dz grid
+// with nodes numbered like MakeGrid(). Each node carves a passage to a
+// random neighbor in the directions given by bias. It is fast, but the maze
+// has long corridors along the far edges of the grid and every path leads
+// diagonally toward one corner. dx, dy, and dz should be in [1,1024].
+// see: http://weblog.jamisbuck.org/2011/2/1/maze-generation-binary-tree-algorithm
+func BinaryTree(dx, dy, dz int, bias Bias) Graph {
+	return BinaryTreeRand(dx, dy, dz, bias, defaultRNG)
+}
+
+// BinaryTreeRand is BinaryTree using rng as the source of randomness.
+func BinaryTreeRand(dx, dy, dz int, bias Bias, rng RNG) Graph {
+	maze, _ := BinaryTreeContext(context.Background(), dx, dy, dz, bias, Options{Rand: rng})
+	return maze
+}
+
+// BinaryTreeContext is BinaryTree controlled by ctx and opts.
+func BinaryTreeContext(ctx context.Context, dx, dy, dz int, bias Bias, opts Options) (Graph, error) {
+	max := 1024
+	dx = clamp(dx, 1, max)
+	dy = clamp(dy, 1, max)
+	dz = clamp(dz, 1, max)
+
+	return build(ctx, dx*dy*dz, opts, func(b *builder) {
+		binaryTree(b, dx, dy, dz, bias)
+	})
+}
+
+func binaryTree(b *builder, dx, dy, dz int, bias Bias) {
+	bx, by, bz := direction(bias.X), direction(bias.Y), direction(bias.Z)
+
+	var options NodeSlice
+	for z := 0; z < dz; z++ {
+		for y := 0; y < dy; y++ {
+			for x := 0; x < dx; x++ {
+				if b.stopped() {
+					return
+				}
+				n := Node(ThreeToOne(x, y, z, dx, dy))
+				b.add(n)
+
+				options = options[:0]
+				if inGrid(x+bx, dx) {
+					options = options.Append(Node(ThreeToOne(x+bx, y, z, dx, dy)))
+				}
+				if inGrid(y+by, dy) {
+					options = options.Append(Node(ThreeToOne(x, y+by, z, dx, dy)))
+				}
+				if inGrid(z+bz, dz) {
+					options = options.Append(Node(ThreeToOne(x, y, z+bz, dx, dy)))
+				}
+				if len(options) > 0 {
+					b.carve(n, options[b.rng.Intn(len(options))])
+				}
+			}
+		}
+	}
+}
+
+// Sidewinder implements the sidewinder algorithm on a dx by dy by dz grid
+// with nodes numbered like MakeGrid(). Each row along x is split into runs
+// of passages in the x direction of bias, and each run is joined to the
+// next row by one passage in the y or z direction of bias. The maze has a
+// single long corridor along the far edge. dx, dy, and dz should be in
+// [1,1024].
+// see: http://weblog.jamisbuck.org/2011/2/3/maze-generation-sidewinder-algorithm
+func Sidewinder(dx, dy, dz int, bias Bias) Graph {
+	return SidewinderRand(dx, dy, dz, bias, defaultRNG)
+}
+
+// SidewinderRand is Sidewinder using rng as the source of randomness.
+func SidewinderRand(dx, dy, dz int, bias Bias, rng RNG) Graph {
+	maze, _ := SidewinderContext(context.Background(), dx, dy, dz, bias, Options{Rand: rng})
+	return maze
+}
+
+// SidewinderContext is Sidewinder controlled by ctx and opts.
+func SidewinderContext(ctx context.Context, dx, dy, dz int, bias Bias, opts Options) (Graph, error) {
+	max := 1024
+	dx = clamp(dx, 1, max)
+	dy = clamp(dy, 1, max)
+	dz = clamp(dz, 1, max)
+
+	return build(ctx, dx*dy*dz, opts, func(b *builder) {
+		sidewinder(b, dx, dy, dz, bias)
+	})
+}
+
+func sidewinder(b *builder, dx, dy, dz int, bias Bias) {
+	bx, by, bz := direction(bias.X), direction(bias.Y), direction(bias.Z)
+
+	var run []int // x coordinates of the current run
+	for z := 0; z < dz; z++ {
+		for y := 0; y < dy; y++ {
+			if b.stopped() {
+				return
+			}
+			// a row which can't be joined to another is one long corridor.
+			canY, canZ := inGrid(y+by, dy), inGrid(z+bz, dz)
+
+			run = run[:0]
+			for i := 0; i < dx; i++ {
+				x := i
+				if bx < 0 {
+					x = dx - 1 - i // runs proceed in the direction of bias
+				}
+				n := Node(ThreeToOne(x, y, z, dx, dy))
+				b.add(n)
+				run = append(run, x)
+
+				if inGrid(x+bx, dx) && (!(canY || canZ) || b.rng.Intn(2) == 0) {
+					b.carve(n, Node(ThreeToOne(x+bx, y, z, dx, dy)))
+					continue
+				}
+
+				// close the run by joining a random node in it to the next row.
+				if canY || canZ {
+					rx, ry, rz := run[b.rng.Intn(len(run))], y, z
+					if canY && (!canZ || b.rng.Intn(2) == 0) {
+						ry += by
+					} else {
+						rz += bz
+					}
+					b.carve(Node(ThreeToOne(rx, y, z, dx, dy)), Node(ThreeToOne(rx, ry, rz, dx, dy)))
+				}
+				run = run[:0]
+			}
+		}
+	}
+}
+
+// box is a region of a grid. lo is the x, y, z position of one corner, which
+// is inside the box, and hi is that of the opposite corner, which is outside.
+type box struct {
+	lo, hi [3]int
+}
+
+// RecursiveDivision implements the recursive division algorithm. Unlike
+// the other algorithms it starts with every passage of MakeGrid(dx, dy, dz)
+// open and removes them. The grid is divided by a wall (a plane in 3D)
+// across its longest side with a single opening in it, and each half is
+// divided again until every region is one node. The result has a boxy,
+// room-like look. dx, dy, and dz should be in [1,1024].
+// see: http://weblog.jamisbuck.org/2011/1/12/maze-generation-recursive-division-algorithm
+func RecursiveDivision(dx, dy, dz int) Graph {
+	return RecursiveDivisionRand(dx, dy, dz, defaultRNG)
+}
+
+// RecursiveDivisionRand is RecursiveDivision using rng as the source of
+// randomness.
+func RecursiveDivisionRand(dx, dy, dz int, rng RNG) Graph {
+	maze, _ := RecursiveDivisionContext(context.Background(), dx, dy, dz, Options{Rand: rng})
+	return maze
+}
+
+// RecursiveDivisionContext is RecursiveDivision controlled by ctx and opts.
+// Progress is reported as the initial grid is made.
+func RecursiveDivisionContext(ctx context.Context, dx, dy, dz int, opts Options) (Graph, error) {
+	max := 1024
+	dx = clamp(dx, 1, max)
+	dy = clamp(dy, 1, max)
+	dz = clamp(dz, 1, max)
+
+	return build(ctx, dx*dy*dz, opts, func(b *builder) {
+		recursiveDivision(b, dx, dy, dz)
+	})
+}
+
+func recursiveDivision(b *builder, dx, dy, dz int) {
+	makeGrid(b, dx, dy, dz)
+	node := func(p [3]int) Node {
+		return Node(ThreeToOne(p[0], p[1], p[2], dx, dy))
+	}
+
+	// an explicit stack of regions left to divide is used instead of recursion.
+	stack := []box{{hi: [3]int{dx, dy, dz}}}
+	for len(stack) > 0 && !b.stopped() {
+		r := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		// divide across the longest axis. ties are broken randomly.
+		axis, longest, ties := 0, 1, 0
+		for i := range r.lo {
+			switch size := r.hi[i] - r.lo[i]; {
+			case size > longest:
+				axis, longest, ties = i, size, 1
+			case size == longest && size > 1:
+				ties++
+				if b.rng.Intn(ties) == 0 {
+					axis = i
+				}
+			}
+		}
+		if longest == 1 {
+			continue // a single node can't be divided
+		}
+
+		// the wall is between w and w+1 on the chosen axis. every passage
+		// through it is removed except the one at the opening.
+		w := r.lo[axis] + b.rng.Intn(longest-1)
+		wall := r
+		wall.lo[axis], wall.hi[axis] = w, w+1
+		var open [3]int
+		for i := range open {
+			open[i] = wall.lo[i] + b.rng.Intn(wall.hi[i]-wall.lo[i])
+		}
+
+		var p [3]int
+		for p[2] = wall.lo[2]; p[2] < wall.hi[2]; p[2]++ {
+			for p[1] = wall.lo[1]; p[1] < wall.hi[1]; p[1]++ {
+				for p[0] = wall.lo[0]; p[0] < wall.hi[0]; p[0]++ {
+					if p == open {
+						continue
+					}
+					q := p
+					q[axis]++
+					b.remove(node(p), node(q))
+				}
+			}
+		}
+
+		// divide both halves.
+		near, far := r, r
+		near.hi[axis], far.lo[axis] = w+1, w+1
+		stack = append(stack, near, far)
+	}
+}
@@ -0,0 +1,134 @@
+package maze
+
+import (
+	"testing"
+)
+
+func TestEller(t *testing.T) {
+	tests := []struct {
+		name       string
+		dx, dy, dz int
+	}{
+		{name: "1x1x1", dx: 1, dy: 1, dz: 1},
+		{name: "rows 10x1x10", dx: 10, dy: 1, dz: 10},
+		{name: "single row", dx: 10, dy: 1, dz: 1},
+		{name: "layers 4x5x6", dx: 4, dy: 5, dz: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maze := NewMapGraph()
+			maze.Add(MakeGrid(tt.dx, tt.dy, tt.dz).Nodes()...)
+			z := 0
+			Eller(tt.dx, tt.dy, tt.dz, func(l Layer) bool {
+				if l.Z != z {
+					t.Errorf("got layer %d, want %d", l.Z, z)
+				}
+				z++
+				for _, e := range append(l.Edges, l.Next...) {
+					maze.AddEdge(e.A, e.B)
+				}
+				return true
+			})
+			if z != tt.dz {
+				t.Errorf("got %d layers, want %d", z, tt.dz)
+			}
+			checkPerfect(t, MakeGrid(tt.dx, tt.dy, tt.dz), maze)
+		})
+	}
+}
+
+func TestEller_endless(t *testing.T) {
+	const want = 500
+	got := 0
+	Eller(8, 1, 0, func(l Layer) bool {
+		got++
+		if len(l.Next) == 0 {
+			t.Fatalf("layer %d of an endless maze has no passage to the next", l.Z)
+		}
+		return got < want
+	})
+	if got != want {
+		t.Errorf("Eller() emitted %d layers, want %d", got, want)
+	}
+}
+
+// biasTests are the grids and biases used to test BinaryTree and Sidewinder.
+var biasTests = []struct {
+	name       string
+	dx, dy, dz int
+	bias       Bias
+}{
+	{name: "2x1x1", dx: 2, dy: 1, dz: 1},
+	{name: "10x1x1", dx: 10, dy: 1, dz: 1, bias: Bias{X: -1}},
+	{name: "10x10 +x+y", dx: 10, dy: 10, dz: 1, bias: Bias{X: 1, Y: 1}},
+	{name: "10x10 -x-y", dx: 10, dy: 10, dz: 1, bias: Bias{X: -1, Y: -1}},
+	{name: "10x10 -x+y", dx: 10, dy: 10, dz: 1, bias: Bias{X: -1, Y: 1}},
+	{name: "4x5x6 +x-y+z", dx: 4, dy: 5, dz: 6, bias: Bias{X: 1, Y: -1, Z: 1}},
+	{name: "4x5x6 -x-y-z", dx: 4, dy: 5, dz: 6, bias: Bias{X: -1, Y: -1, Z: -1}},
+	{name: "1x5x6 -z", dx: 1, dy: 5, dz: 6, bias: Bias{Z: -1}},
+}
+
+// checkCorridor fails the test if the row of maze at y, z is not one long
+// corridor along x.
+func checkCorridor(t *testing.T, maze Graph, y, z, dx, dy int) {
+	t.Helper()
+	for x := 0; x < dx-1; x++ {
+		a, b := Node(ThreeToOne(x, y, z, dx, dy)), Node(ThreeToOne(x+1, y, z, dx, dy))
+		if !maze.HasEdge(a, b) {
+			t.Errorf("row y=%d z=%d is missing passage (%v)-(%v)", y, z, a, b)
+		}
+	}
+}
+
+// farEdge gives the coordinate of the edge of an axis toward which bias
+// points.
+func farEdge(bias, size int) int {
+	if bias < 0 {
+		return 0
+	}
+	return size - 1
+}
+
+func TestBinaryTree(t *testing.T) {
+	for _, tt := range biasTests {
+		t.Run(tt.name, func(t *testing.T) {
+			maze := BinaryTree(tt.dx, tt.dy, tt.dz, tt.bias)
+			checkPerfect(t, MakeGrid(tt.dx, tt.dy, tt.dz), maze)
+			checkCorridor(t, maze, farEdge(tt.bias.Y, tt.dy), farEdge(tt.bias.Z, tt.dz), tt.dx, tt.dy)
+		})
+	}
+}
+
+func TestSidewinder(t *testing.T) {
+	for _, tt := range biasTests {
+		t.Run(tt.name, func(t *testing.T) {
+			maze := Sidewinder(tt.dx, tt.dy, tt.dz, tt.bias)
+			checkPerfect(t, MakeGrid(tt.dx, tt.dy, tt.dz), maze)
+			checkCorridor(t, maze, farEdge(tt.bias.Y, tt.dy), farEdge(tt.bias.Z, tt.dz), tt.dx, tt.dy)
+		})
+	}
+}
+
+func TestRecursiveDivision(t *testing.T) {
+	tests := []struct {
+		name       string
+		dx, dy, dz int
+	}{
+		{name: "1x1x1", dx: 1, dy: 1, dz: 1},
+		{name: "2x1x1", dx: 2, dy: 1, dz: 1},
+		{name: "10x1x1", dx: 10, dy: 1, dz: 1},
+		{name: "10x10x1", dx: 10, dy: 10, dz: 1},
+		{name: "3x17x1", dx: 3, dy: 17, dz: 1},
+		{name: "4x5x6", dx: 4, dy: 5, dz: 6},
+		{name: "1x1x8", dx: 1, dy: 1, dz: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maze := RecursiveDivision(tt.dx, tt.dy, tt.dz)
+			if want := tt.dx * tt.dy * tt.dz; maze.NodeCount() != want {
+				t.Fatalf("maze has %d nodes, want %d", maze.NodeCount(), want)
+			}
+			checkPerfect(t, MakeGrid(tt.dx, tt.dy, tt.dz), maze)
+		})
+	}
+}
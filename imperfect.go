@@ -0,0 +1,190 @@
+package maze
+
+import (
+	"sort"
+)
+
+// The functions in this file turn perfect mazes, which have exactly one path
+// between any two nodes, into imperfect ones with loops.
+
+// Braid removes dead ends from maze. Each dead end, a node with only one
+// neighbor, is connected with probability p to another of its neighbors in
+// grid, which should be the graph maze was generated from. Neighbors which
+// are also dead ends are preferred, so that both are removed by one new
+// passage. With p=1 every dead end that can be removed is.
+// see: http://weblog.jamisbuck.org/2011/3/4/maze-generation-braiding
+func Braid(maze, grid Graph, p float64) {
+	BraidRand(maze, grid, p, defaultRNG)
+}
+
+// BraidRand is Braid using rng as the source of randomness.
+func BraidRand(maze, grid Graph, p float64, rng RNG) {
+	var candidates, deadEnds NodeSlice
+	for _, n := range maze.Nodes() {
+		if len(maze.Neighbors(n)) != 1 || rng.Float64() >= p {
+			continue // not a dead end (perhaps no longer), or left alone
+		}
+
+		candidates, deadEnds = candidates[:0], deadEnds[:0]
+		for _, neighbor := range grid.Neighbors(n) {
+			if !maze.Has(neighbor) || maze.HasEdge(n, neighbor) {
+				continue
+			}
+			candidates = candidates.Append(neighbor)
+			if len(maze.Neighbors(neighbor)) == 1 {
+				deadEnds = deadEnds.Append(neighbor)
+			}
+		}
+		if len(deadEnds) > 0 {
+			candidates = deadEnds
+		}
+		if len(candidates) > 0 {
+			maze.AddEdge(n, candidates[rng.Intn(len(candidates))])
+		}
+	}
+}
+
+// LoopPolicy chooses which edges AddLoops adds to a maze.
+type LoopPolicy int
+
+const (
+	// LoopsRandom picks edges uniformly at random.
+	LoopsRandom LoopPolicy = iota
+	// LoopsLong prefers edges joining nodes which are far apart in the maze,
+	// making long shortcuts.
+	LoopsLong
+	// LoopsShort prefers edges joining nodes which are close together in
+	// the maze, making small loops.
+	LoopsShort
+)
+
+// AddLoops adds up to n edges of grid, which should be the graph maze was
+// generated from, to maze, choosing them according to policy. Distances for
+// LoopsLong and LoopsShort are measured in maze as it was before any edge
+// was added, and ties are broken randomly. It returns the number of edges
+// added, which is less than n if grid has no more edges to add.
+func AddLoops(maze, grid Graph, n int, policy LoopPolicy) int {
+	return AddLoopsRand(maze, grid, n, policy, defaultRNG)
+}
+
+// AddLoopsRand is AddLoops using rng as the source of randomness.
+func AddLoopsRand(maze, grid Graph, n int, policy LoopPolicy, rng RNG) int {
+	candidates := loopCandidates(maze, grid)
+	shuffle(rng, len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	if policy == LoopsLong || policy == LoopsShort {
+		dist := treeDistances(maze, candidates)
+		order := make([]int, len(candidates))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			if policy == LoopsLong {
+				return dist[order[i]] > dist[order[j]]
+			}
+			return dist[order[i]] < dist[order[j]]
+		})
+		sorted := make([]Edge, len(candidates))
+		for i, k := range order {
+			sorted[i] = candidates[k]
+		}
+		candidates = sorted
+	}
+
+	n = clamp(n, 0, len(candidates))
+	for _, e := range candidates[:n] {
+		maze.AddEdge(e.A, e.B)
+	}
+	return n
+}
+
+// LoopFraction gives the number of edges to pass to AddLoops to add the
+// fraction f, in [0,1], of the edges of grid which are not in maze.
+func LoopFraction(maze, grid Graph, f float64) int {
+	return int(f*float64(len(loopCandidates(maze, grid))) + 0.5)
+}
+
+// loopCandidates lists the edges of grid which can be added to maze.
+func loopCandidates(maze, grid Graph) []Edge {
+	var edges []Edge
+	for _, e := range Edges(grid) {
+		if maze.Has(e.A) && maze.Has(e.B) && !maze.HasEdge(e.A, e.B) {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// treeDistances gives the distance in maze between the nodes of each edge,
+// using Tarjan's offline lowest common ancestor algorithm on a depth-first
+// spanning tree of maze. If maze is perfect, that is the only path. Nodes in
+// different components are given a distance longer than any path.
+func treeDistances(maze Graph, edges []Edge) []int {
+	type query struct {
+		other Node
+		i     int
+	}
+	queries := make(map[Node][]query)
+	for i, e := range edges {
+		queries[e.A] = append(queries[e.A], query{e.B, i})
+		queries[e.B] = append(queries[e.B], query{e.A, i})
+	}
+
+	dist := make([]int, len(edges))
+	for i := range dist {
+		dist[i] = maze.NodeCount()
+	}
+	depth := make(map[Node]int)
+	root := make(map[Node]Node)
+	finished := make(map[Node]bool)
+	ancestor := make(map[Node]Node)
+	sets := NewDisjointSet()
+
+	type frame struct {
+		n Node
+		i int // next neighbor to look at
+	}
+	var stack []frame
+	enter := func(n, r Node, d int) {
+		depth[n], root[n], ancestor[n] = d, r, n
+		sets.Add(n)
+		stack = append(stack, frame{n: n})
+	}
+	for _, r := range maze.Nodes() {
+		if _, seen := depth[r]; seen {
+			continue
+		}
+		enter(r, r, 0)
+		for len(stack) > 0 {
+			f := &stack[len(stack)-1]
+			if neighbors := maze.Neighbors(f.n); f.i < len(neighbors) {
+				next := neighbors[f.i]
+				f.i++
+				if _, seen := depth[next]; !seen {
+					enter(next, r, depth[f.n]+1)
+				}
+				continue
+			}
+
+			// all of n's subtree is done, so the lowest common ancestor of n
+			// and any finished node in the same tree is known.
+			n := f.n
+			stack = stack[:len(stack)-1]
+			finished[n] = true
+			for _, q := range queries[n] {
+				if finished[q.other] && root[q.other] == r {
+					lca := ancestor[sets.Find(q.other)]
+					dist[q.i] = depth[n] + depth[q.other] - 2*depth[lca]
+				}
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1].n
+				sets.Union(parent, n)
+				ancestor[sets.Find(parent)] = parent
+			}
+		}
+	}
+	return dist
+}
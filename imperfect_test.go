@@ -0,0 +1,170 @@
+package maze
+
+import (
+	"testing"
+)
+
+// deadEnds counts the nodes in g with one neighbor.
+func deadEnds(g Graph) int {
+	count := 0
+	for _, n := range g.Nodes() {
+		if len(g.Neighbors(n)) == 1 {
+			count++
+		}
+	}
+	return count
+}
+
+func TestBraid(t *testing.T) {
+	tests := []struct {
+		name string
+		grid Graph
+		p    float64
+		want func(before, after int) bool
+	}{
+		{name: "p=0 keeps all", grid: MakeGrid(10, 10, 1), p: 0,
+			want: func(before, after int) bool { return before == after }},
+		{name: "p=1 removes all", grid: MakeGrid(10, 10, 1), p: 1,
+			want: func(before, after int) bool { return after == 0 }},
+		{name: "p=1 removes all 3D", grid: MakeGrid(4, 4, 4), p: 1,
+			want: func(before, after int) bool { return after == 0 }},
+		{name: "p=0.5 removes some", grid: MakeGrid(30, 30, 1), p: 0.5,
+			want: func(before, after int) bool { return 0 < after && after < before }},
+		{name: "corridor can't be braided", grid: MakeGrid(10, 1, 1), p: 1,
+			want: func(before, after int) bool { return after == 2 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maze := Backtracker(tt.grid)
+			before := deadEnds(maze)
+			edges := len(Edges(maze))
+
+			Braid(maze, tt.grid, tt.p)
+
+			after := deadEnds(maze)
+			if !tt.want(before, after) {
+				t.Errorf("Braid(%v) changed dead ends from %d to %d", tt.p, before, after)
+			}
+			for _, e := range Edges(maze) {
+				if !tt.grid.HasEdge(e.A, e.B) {
+					t.Errorf("Braid() added (%v)-(%v) which is not in grid", e.A, e.B)
+				}
+			}
+			if added := len(Edges(maze)) - edges; added > before {
+				t.Errorf("Braid() added %d edges for %d dead ends", added, before)
+			}
+		})
+	}
+}
+
+// distance finds the length of the shortest path from a to b in g by
+// breadth-first search, or -1 if there is none.
+func distance(g Graph, a, b Node) int {
+	dist := map[Node]int{a: 0}
+	queue := NodeSlice{a}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if n == b {
+			return dist[n]
+		}
+		for _, next := range g.Neighbors(n) {
+			if _, seen := dist[next]; !seen {
+				dist[next] = dist[n] + 1
+				queue = queue.Append(next)
+			}
+		}
+	}
+	return -1
+}
+
+func Test_treeDistances(t *testing.T) {
+	for _, grid := range []Graph{MakeGrid(12, 9, 1), MakeGrid(4, 4, 4), maskedGrid()} {
+		maze := KruskalRand(grid, NewPCG(3, 0))
+		candidates := loopCandidates(maze, grid)
+		for i, d := range treeDistances(maze, candidates) {
+			e := candidates[i]
+			want := distance(maze, e.A, e.B)
+			if want == -1 {
+				want = maze.NodeCount()
+			}
+			if d != want {
+				t.Errorf("distance (%v)-(%v) = %d, want %d", e.A, e.B, d, want)
+			}
+		}
+	}
+}
+
+func TestAddLoops(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy LoopPolicy
+		n      int
+	}{
+		{"random", LoopsRandom, 10},
+		{"long", LoopsLong, 10},
+		{"short", LoopsShort, 10},
+		{"none", LoopsLong, 0},
+		{"negative", LoopsShort, -5},
+		{"too many", LoopsRandom, 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grid := MakeGrid(10, 10, 1)
+			maze := WilsonRand(grid, NewPCG(1, 0))
+			tree := WilsonRand(grid, NewPCG(1, 0))
+			candidates := loopCandidates(maze, grid)
+
+			added := AddLoopsRand(maze, grid, tt.n, tt.policy, NewPCG(2, 0))
+			want := clamp(tt.n, 0, len(candidates))
+			if added != want || len(Edges(maze)) != 99+want {
+				t.Fatalf("added %d edges, maze has %d, want %d added", added, len(Edges(maze)), want)
+			}
+
+			// every edge added must be at least as long (or short) as every
+			// edge left out.
+			longest, shortest := 0, grid.NodeCount()
+			inLongest, inShortest := 0, grid.NodeCount()
+			for _, e := range candidates {
+				d := distance(tree, e.A, e.B)
+				if maze.HasEdge(e.A, e.B) {
+					inLongest, inShortest = imax(inLongest, d), imin(inShortest, d)
+				} else {
+					longest, shortest = imax(longest, d), imin(shortest, d)
+				}
+			}
+			if tt.policy == LoopsLong && added > 0 && added < len(candidates) && inShortest < longest {
+				t.Errorf("added edge of length %d but left out one of %d", inShortest, longest)
+			}
+			if tt.policy == LoopsShort && added > 0 && added < len(candidates) && inLongest > shortest {
+				t.Errorf("added edge of length %d but left out one of %d", inLongest, shortest)
+			}
+		})
+	}
+}
+
+func TestLoopFraction(t *testing.T) {
+	grid := MakeGrid(10, 10, 1)
+	maze := Backtracker(grid)
+	if n := LoopFraction(maze, grid, 0.5); n != 41 {
+		t.Errorf("LoopFraction(0.5) = %d, want 41", n)
+	}
+	AddLoops(maze, grid, LoopFraction(maze, grid, 1), LoopsRandom)
+	if got, want := len(Edges(maze)), len(Edges(grid)); got != want {
+		t.Errorf("maze has %d edges after adding all, want %d", got, want)
+	}
+}
+
+func imax(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func imin(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,63 @@
+package maze
+
+// indexedSet is a set of nodes which can add, remove, and pick a random node
+// in constant time.
+type indexedSet struct {
+	nodes NodeSlice
+	index map[Node]int // position of each node in nodes
+}
+
+// newIndexedSet makes a set containing nodes.
+func newIndexedSet(nodes NodeSlice) *indexedSet {
+	s := &indexedSet{
+		nodes: make(NodeSlice, 0, len(nodes)),
+		index: make(map[Node]int, len(nodes)),
+	}
+	s.Add(nodes...)
+	return s
+}
+
+// Has returns true if n is in the set.
+func (s *indexedSet) Has(n Node) bool {
+	_, in := s.index[n]
+	return in
+}
+
+// Add adds the node(s) to the set.
+func (s *indexedSet) Add(nodes ...Node) {
+	for _, n := range nodes {
+		if !s.Has(n) {
+			s.index[n] = len(s.nodes)
+			s.nodes = s.nodes.Append(n)
+		}
+	}
+}
+
+// Remove removes the node(s) from the set.
+func (s *indexedSet) Remove(nodes ...Node) {
+	for _, n := range nodes {
+		i, in := s.index[n]
+		if !in {
+			continue
+		}
+		// removeAt moves the last node into i.
+		s.nodes = s.nodes.removeAt(i)
+		if i < len(s.nodes) {
+			s.index[s.nodes[i]] = i
+		}
+		delete(s.index, n)
+	}
+}
+
+// Random returns a random node from the set, or nil if it is empty.
+func (s *indexedSet) Random(rng RNG) Node {
+	if len(s.nodes) == 0 {
+		return nil
+	}
+	return s.nodes[rng.Intn(len(s.nodes))]
+}
+
+// Len returns the number of nodes in the set.
+func (s *indexedSet) Len() int {
+	return len(s.nodes)
+}
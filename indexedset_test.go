@@ -0,0 +1,50 @@
+package maze
+
+import (
+	"testing"
+)
+
+func Test_indexedSet(t *testing.T) {
+	tests := []struct {
+		name   string
+		add    NodeSlice
+		remove NodeSlice
+		want   NodeSlice
+	}{
+		{name: "empty"},
+		{name: "add", add: NodeSlice{0, 1, 2}, want: NodeSlice{0, 1, 2}},
+		{name: "add dups", add: NodeSlice{0, 1, 0}, want: NodeSlice{0, 1}},
+		{name: "remove middle", add: NodeSlice{0, 1, 2, 3}, remove: NodeSlice{1}, want: NodeSlice{0, 2, 3}},
+		{name: "remove last", add: NodeSlice{0, 1, 2}, remove: NodeSlice{2}, want: NodeSlice{0, 1}},
+		{name: "remove all", add: NodeSlice{0, 1, 2}, remove: NodeSlice{2, 0, 1}},
+		{name: "remove missing", add: NodeSlice{0, 1}, remove: NodeSlice{5}, want: NodeSlice{0, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newIndexedSet(tt.add)
+			s.Remove(tt.remove...)
+			if s.Len() != len(tt.want) {
+				t.Errorf("indexedSet.Len() = %v, want %v", s.Len(), len(tt.want))
+			}
+			for _, n := range tt.want {
+				if !s.Has(n) {
+					t.Errorf("indexedSet is missing (%v)", n)
+				}
+			}
+			for _, n := range tt.remove {
+				if s.Has(n) {
+					t.Errorf("indexedSet still has removed (%v)", n)
+				}
+			}
+			// the index must still match after removals.
+			for i, n := range s.nodes {
+				if s.index[n] != i {
+					t.Errorf("indexedSet.index[%v] = %v, want %v", n, s.index[n], i)
+				}
+			}
+			if n := s.Random(NewPCG(1, 0)); (n == nil) != (len(tt.want) == 0) || n != nil && !s.Has(n) {
+				t.Errorf("indexedSet.Random() = %v", n)
+			}
+		})
+	}
+}
@@ -1,21 +1,13 @@
 package maze
 
-import (
-	"math/rand"
-)
-
-// TODO: for future consideration.
-// type void struct{}
-// type nodeSet map[Node]void
-
 // ID is used to uniquely identify nodes.
 type ID = uint32
 
 const maxID = 1<<32 - 1
 
-func randID(max int) ID {
+func randID(max int, rng RNG) ID {
 	n := clamp(max, 1, maxID)
-	return ID(rand.Intn(n))
+	return ID(rng.Intn(n))
 }
 
 // Node allows types to become "nodes" or "cells" in a maze, which is
@@ -26,8 +18,19 @@ type Node interface {
 	// SetID(ID) Node
 }
 
+// NodeSet is anything which can report whether it contains a node. Every
+// Graph is a NodeSet.
+type NodeSet interface {
+	Has(Node) bool
+}
+
 // Graph provides the basic interface needed by maze making algorithms, since
 // mazes are essentially just undirected graphs.
+//
+// Nodes was added to Graph after the other methods, since the algorithms
+// need to visit every node, even in graphs which aren't connected, in a
+// repeatable order. Graph types defined outside this package must add it to
+// keep implementing Graph.
 type Graph interface {
 	// Has returns true if the graph contains Node.
 	Has(Node) bool
@@ -51,4 +54,7 @@ type Graph interface {
 	RandomNode() Node
 	// NodeCount gives the number of nodes in the graph.
 	NodeCount() int
+	// Nodes provides a list of all the nodes in the graph. Algorithms rely
+	// on the list being in the same order every time for the same graph.
+	Nodes() NodeSlice
 }
@@ -1,9 +1,5 @@
 package maze
 
-import (
-	"math/rand"
-)
-
 // mapgraph maintains an undirected graph of Nodes and edges using a map.
 type mapgraph map[Node]NodeSlice
 
@@ -81,10 +77,11 @@ func (g mapgraph) RemoveEdge(a, b Node) {
 	}
 }
 
-// RandomNode returns a random node from the graph. It assumes math/rand's
-// default source has already been seeded.
+// RandomNode returns a random node from the graph using the package's default
+// source of randomness. The result also depends on map iteration order, so
+// use RandomNode(g, rng) when the choice must be reproducible.
 func (g mapgraph) RandomNode() Node {
-	i, n := 0, rand.Intn(len(g))
+	i, n := 0, defaultRNG.Intn(len(g))
 	for k := range g {
 		if i == n {
 			return k
@@ -99,12 +96,24 @@ func (g mapgraph) NodeCount() int {
 	return len(g)
 }
 
+// Nodes returns all the nodes in the graph. Since map iteration order is
+// random, the nodes are sorted so that the result is always in the same
+// order.
+func (g mapgraph) Nodes() NodeSlice {
+	nodes := make(NodeSlice, 0, len(g))
+	for n := range g {
+		nodes = append(nodes, n)
+	}
+	nodes.sort()
+	return nodes
+}
+
 // assignUnusedID assigns an id to n.
 // func (g mapgraph) assignUnusedID(n Node) {
 // 	var id ID
 // 	used := true
 // 	for used {
-// 		id := randID(maxID)
+// 		id := randID(maxID, defaultRNG)
 // 		_, used = g[id]
 // 	}
 // 	n.SetID(id)
@@ -260,3 +260,26 @@ func Test_mapgraph_NodeCount(t *testing.T) {
 		})
 	}
 }
+
+func Test_mapgraph_Nodes(t *testing.T) {
+	tests := []struct {
+		name string
+		g    Graph
+	}{
+		{name: "empty", g: NewMapGraph()},
+		{name: "normal", g: constructGraph1()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.g.Nodes()
+			if len(got) != tt.g.NodeCount() {
+				t.Errorf("len(mapgraph.Nodes()) = %v, want %v", len(got), tt.g.NodeCount())
+			}
+			for _, n := range got {
+				if !tt.g.Has(n) {
+					t.Errorf("mapgraph.Nodes() returned (%v) which is not in g", n)
+				}
+			}
+		})
+	}
+}
@@ -1,5 +1,12 @@
 package maze
 
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
 // NodeSlice is a slice of Nodes.
 type NodeSlice []Node
 
@@ -98,3 +105,151 @@ func (slice NodeSlice) pop() (NodeSlice, Node) {
 	n := slice[l-1]
 	return slice.removeAt(l - 1), n
 }
+
+// sort orders the slice in place so that the same nodes always end up in
+// the same order. See nodeLess. Each node's sort key is made once, so that
+// sorting nodes other than ints doesn't use reflection for every comparison.
+func (slice NodeSlice) sort() {
+	ints := true
+	for _, n := range slice {
+		if _, ok := n.(int); !ok {
+			ints = false
+			break
+		}
+	}
+	if ints { // fast path for the nodes made by MakeGrid()
+		sort.Slice(slice, func(i, j int) bool {
+			return slice[i].(int) < slice[j].(int)
+		})
+		return
+	}
+
+	keys := make([]nodeKey, len(slice))
+	for i, n := range slice {
+		keys[i] = makeNodeKey(n)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].less(&keys[j])
+	})
+	for i := range keys {
+		slice[i] = keys[i].node
+	}
+}
+
+// nodeLess defines a total order over nodes, with one exception. Nil nodes
+// come first. Nodes of different types are ordered by type name, then by
+// package path. Integers, floats, and strings are compared by value, with
+// NaNs before other floats, and nodes of any other type by their formatted
+// value. Pointers which format the same, such as two pointers to equal
+// structs, are ordered by address. That order is the same every time for the
+// same graph, but not between runs of a program, so mazes made from such
+// nodes can't be reproduced from a seed.
+//
+// The exception is types with the same name declared in different functions
+// of the same package. They can't be told apart, so nodes of such types which
+// format the same aren't ordered, and should not be mixed in one graph.
+func nodeLess(a, b Node) bool {
+	if a, ok := a.(int); ok { // fast path for the nodes made by MakeGrid()
+		if b, ok := b.(int); ok {
+			return a < b
+		}
+	}
+	ka, kb := makeNodeKey(a), makeNodeKey(b)
+	return ka.less(&kb)
+}
+
+// the kinds of nodes, in the order nodeLess puts them within a type.
+const (
+	keyNil = iota
+	keySigned
+	keyUnsigned
+	keyNaN
+	keyFloat
+	keyString
+	keyOther
+)
+
+// nodeKey holds what nodeLess compares about a node, found using reflection
+// once rather than for every comparison.
+type nodeKey struct {
+	node      Node
+	typ, pkg  string // type name and package path
+	kind      int
+	i         int64
+	u         uint64 // unsigned values, NaN bits, and pointer addresses
+	f         float64
+	s         string // string values and formatted values
+	isPointer bool
+}
+
+func makeNodeKey(n Node) nodeKey {
+	k := nodeKey{node: n}
+	v := reflect.ValueOf(n)
+	if !v.IsValid() {
+		return k
+	}
+	k.typ, k.pkg = v.Type().String(), pkgPath(v.Type())
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		k.kind, k.i = keySigned, v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		k.kind, k.u = keyUnsigned, v.Uint()
+	case reflect.Float32, reflect.Float64:
+		k.kind, k.f = keyFloat, v.Float()
+		if math.IsNaN(k.f) {
+			k.kind, k.u = keyNaN, math.Float64bits(k.f)
+		}
+	case reflect.String:
+		k.kind, k.s = keyString, v.String()
+	default:
+		k.kind, k.s = keyOther, fmt.Sprintf("%#v", n)
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Chan, reflect.UnsafePointer:
+			k.isPointer, k.u = true, uint64(v.Pointer())
+		}
+	}
+	return k
+}
+
+// pkgPath gives the path of the package which declared t, or of the type t
+// is made from, such as the element type of a pointer.
+func pkgPath(t reflect.Type) string {
+	for t.Name() == "" {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan, reflect.Map:
+			t = t.Elem()
+		default:
+			return ""
+		}
+	}
+	return t.PkgPath()
+}
+
+func (k *nodeKey) less(o *nodeKey) bool {
+	switch {
+	case k.typ != o.typ:
+		return k.typ < o.typ
+	case k.pkg != o.pkg:
+		return k.pkg < o.pkg
+	case k.kind != o.kind:
+		return k.kind < o.kind
+	}
+
+	switch k.kind {
+	case keySigned:
+		return k.i < o.i
+	case keyUnsigned, keyNaN:
+		return k.u < o.u
+	case keyFloat:
+		return k.f < o.f
+	case keyString:
+		return k.s < o.s
+	case keyOther:
+		if k.s != o.s {
+			return k.s < o.s
+		}
+		return k.isPointer && k.u < o.u
+	}
+	return false
+}
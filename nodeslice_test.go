@@ -1,8 +1,12 @@
 package maze
 
 import (
+	"fmt"
+	htmltemplate "html/template"
+	"math"
 	"reflect"
 	"testing"
+	texttemplate "text/template"
 )
 
 func TestNodeSlice_Append(t *testing.T) {
@@ -215,3 +219,84 @@ func TestNodeSlice_pop(t *testing.T) {
 // 		})
 // 	}
 // }
+
+func TestNodeSlice_sort(t *testing.T) {
+	type point struct{ x, y int }
+	tests := []struct {
+		name  string
+		slice NodeSlice
+		want  NodeSlice
+	}{
+		{name: "empty", slice: NodeSlice{}, want: NodeSlice{}},
+		{name: "ints", slice: NodeSlice{3, -1, 10, 0}, want: NodeSlice{-1, 0, 3, 10}},
+		{name: "uint32", slice: NodeSlice{ID(3), ID(1)}, want: NodeSlice{ID(1), ID(3)}},
+		{name: "strings", slice: NodeSlice{"b", "c", "a"}, want: NodeSlice{"a", "b", "c"}},
+		{name: "mixed types", slice: NodeSlice{"a", 1, nil}, want: NodeSlice{nil, 1, "a"}},
+		{name: "structs", slice: NodeSlice{point{1, 2}, point{0, 5}, point{1, 0}},
+			want: NodeSlice{point{0, 5}, point{1, 0}, point{1, 2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.slice.sort()
+			if !reflect.DeepEqual(tt.slice, tt.want) {
+				t.Errorf("NodeSlice.sort() = %v, want %v", tt.slice, tt.want)
+			}
+		})
+	}
+}
+
+func Test_nodeLess(t *testing.T) {
+	type point struct{ x, y int }
+	p, q := &point{1, 2}, &point{1, 2}
+	nan, otherNaN := math.NaN(), math.Float64frombits(math.Float64bits(math.NaN())+1)
+
+	// each pair is of distinct nodes, which must not tie.
+	tests := []struct {
+		name string
+		a, b Node
+	}{
+		{"ints", 1, 2},
+		{"floats", 1.5, -2.0},
+		{"NaN and float", nan, 1.0},
+		{"NaNs", nan, otherNaN},
+		{"mixed types", "1", 1},
+		{"structs", point{1, 2}, point{2, 1}},
+		{"equal pointers", p, q},
+		{"same type name", (*htmltemplate.Template)(nil), (*texttemplate.Template)(nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ab, ba := nodeLess(tt.a, tt.b), nodeLess(tt.b, tt.a); ab == ba {
+				t.Errorf("nodeLess(%v, %v) = %v and nodeLess(%v, %v) = %v", tt.a, tt.b, ab, tt.b, tt.a, ba)
+			}
+			if nodeLess(tt.a, tt.a) {
+				t.Errorf("nodeLess(%v, %v) = true", tt.a, tt.a)
+			}
+		})
+	}
+}
+
+func TestNodeSlice_sort_matchesNodeLess(t *testing.T) {
+	type point struct{ x, y int }
+	p := &point{1, 2}
+	nodes := NodeSlice{3, "a", nil, point{2, 1}, 1.5, math.NaN(), ID(7), p, &point{1, 2}, point{1, 2}, -4, "b"}
+	rng := NewPCG(1, 0)
+
+	var want NodeSlice
+	for i := 0; i < 20; i++ {
+		shuffle(rng, len(nodes), func(i, j int) {
+			nodes[i], nodes[j] = nodes[j], nodes[i]
+		})
+		nodes.sort()
+		for j := 1; j < len(nodes); j++ {
+			if nodeLess(nodes[j], nodes[j-1]) {
+				t.Fatalf("%v is sorted after %v", nodes[j-1], nodes[j])
+			}
+		}
+		if want == nil {
+			want = append(NodeSlice(nil), nodes...)
+		} else if fmt.Sprintf("%#v", nodes) != fmt.Sprintf("%#v", want) {
+			t.Fatalf("sort() = %#v, want %#v", nodes, want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package maze
+
+import (
+	"context"
+	"fmt"
+)
+
+// Options controls the Context variants of the algorithms.
+type Options struct {
+	// Rand is the source of randomness. If nil, the package's default
+	// source is used.
+	Rand RNG
+
+	// Progress, if not nil, is called each time a node is added to the
+	// maze with the number of nodes added so far and the number there will
+	// be when the maze is finished.
+	Progress func(added, total int)
+
+	// MaxNodes is the largest number of nodes a maze may have. Generation
+	// fails with a *BudgetError before anything is allocated if the maze
+	// would be bigger. Zero means there is no limit.
+	MaxNodes int
+
+	// Observe, if not nil, is called with each step taken while making the
+	// maze. Passing the events to Replay() rebuilds the maze. See Stream().
+	Observe func(Event)
+}
+
+// rand returns the RNG to use.
+func (o Options) rand() RNG {
+	if o.Rand == nil {
+		return defaultRNG
+	}
+	return o.Rand
+}
+
+// BudgetError is returned when a maze would have more nodes than allowed by
+// Options.MaxNodes.
+type BudgetError struct {
+	Nodes    int // nodes the maze would have
+	MaxNodes int // the limit
+}
+
+func (e *BudgetError) Error() string {
+	return fmt.Sprintf("maze: %d nodes exceeds the limit of %d", e.Nodes, e.MaxNodes)
+}
+
+// checkInterval is how many steps a generator takes between checks of its
+// context, since checking every step would slow it down.
+const checkInterval = 1024
+
+// builder holds the state shared by the generators while they make a maze:
+// the maze itself, the source of randomness, and the caller's options.
+type builder struct {
+	ctx   context.Context
+	opts  Options
+	rng   RNG
+	maze  Graph
+	total int // nodes the maze will have when finished
+	added int // nodes added to the maze so far
+	steps int // calls to stopped()
+	err   error
+}
+
+// build checks that a maze of total nodes fits in the budget given by opts,
+// then runs generate. It returns the finished maze, or the reason generation
+// stopped early.
+func build(ctx context.Context, total int, opts Options, generate func(*builder)) (Graph, error) {
+	if opts.MaxNodes > 0 && total > opts.MaxNodes {
+		return nil, &BudgetError{Nodes: total, MaxNodes: opts.MaxNodes}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	b := &builder{
+		ctx:   ctx,
+		opts:  opts,
+		rng:   opts.rand(),
+		maze:  NewMapGraph(),
+		total: total,
+	}
+
+	generate(b)
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.maze, nil
+}
+
+// add adds the node(s) to the maze.
+func (b *builder) add(nodes ...Node) {
+	for _, n := range nodes {
+		if !b.maze.Has(n) {
+			b.maze.Add(n)
+			b.emit(NodeVisited, n, nil)
+			b.advance(1)
+		}
+	}
+}
+
+// carve adds a passage between x and y to the maze, adding the nodes if
+// they are not already in it.
+func (b *builder) carve(x, y Node) {
+	b.add(x, y)
+	b.maze.AddEdge(x, y)
+	b.emit(EdgeCarved, x, y)
+}
+
+// remove removes the passage between x and y from the maze.
+func (b *builder) remove(x, y Node) {
+	b.maze.RemoveEdge(x, y)
+	b.emit(EdgeRemoved, x, y)
+}
+
+// emit passes an event to the Observe option.
+func (b *builder) emit(kind EventKind, x, y Node) {
+	if b.opts.Observe != nil {
+		b.opts.Observe(Event{Kind: kind, A: x, B: y})
+	}
+}
+
+// advance reports that n more nodes of the maze are finished.
+func (b *builder) advance(n int) {
+	b.added += n
+	if b.opts.Progress != nil {
+		b.opts.Progress(b.added, b.total)
+	}
+}
+
+// stopped returns true if generation should stop because the context is
+// done. Generators call it once per step of their main loops.
+func (b *builder) stopped() bool {
+	if b.err == nil {
+		b.steps++
+		if b.steps%checkInterval == 0 {
+			b.err = b.ctx.Err()
+		}
+	}
+	return b.err != nil
+}
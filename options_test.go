@@ -0,0 +1,142 @@
+package maze
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// contextGenerators are the Context variants of the generators, made to
+// work on a dx by dy by dz grid.
+var contextGenerators = []struct {
+	name     string
+	generate func(ctx context.Context, dx, dy, dz int, opts Options) (Graph, error)
+}{
+	{name: "MakeGrid", generate: MakeGridContext},
+	{name: "Wilson", generate: onGrid(WilsonContext)},
+	{name: "Backtracker", generate: onGrid(BacktrackerContext)},
+	{name: "Kruskal", generate: onGrid(KruskalContext)},
+	{name: "Prim", generate: onGrid(PrimContext)},
+	{name: "AldousBroder", generate: onGrid(AldousBroderContext)},
+	{name: "HuntAndKill", generate: onGrid(HuntAndKillContext)},
+	{name: "GrowingTree", generate: onGrid(func(ctx context.Context, g Graph, opts Options) (Graph, error) {
+		return GrowingTreeContext(ctx, g, SelectRandom, opts)
+	})},
+	{name: "BinaryTree", generate: func(ctx context.Context, dx, dy, dz int, opts Options) (Graph, error) {
+		return BinaryTreeContext(ctx, dx, dy, dz, Bias{}, opts)
+	}},
+	{name: "Sidewinder", generate: func(ctx context.Context, dx, dy, dz int, opts Options) (Graph, error) {
+		return SidewinderContext(ctx, dx, dy, dz, Bias{}, opts)
+	}},
+	{name: "RecursiveDivision", generate: RecursiveDivisionContext},
+	{name: "Tiled", generate: func(ctx context.Context, dx, dy, dz int, opts Options) (Graph, error) {
+		return TiledContext(ctx, dx, dy, dz, 4, WilsonContext, opts)
+	}},
+	{name: "Eller", generate: func(ctx context.Context, dx, dy, dz int, opts Options) (Graph, error) {
+		maze := NewMapGraph()
+		err := EllerContext(ctx, dx, dy, dz, func(l Layer) bool {
+			for i := 0; i < dx*dy; i++ {
+				maze.Add(ThreeToOne(i%dx, i/dx, l.Z, dx, dy))
+			}
+			for _, e := range append(l.Edges, l.Next...) {
+				maze.AddEdge(e.A, e.B)
+			}
+			return true
+		}, opts)
+		return maze, err
+	}},
+}
+
+// onGrid adapts a generator to make a maze from MakeGrid(dx, dy, dz).
+func onGrid(generate func(context.Context, Graph, Options) (Graph, error)) func(context.Context, int, int, int, Options) (Graph, error) {
+	return func(ctx context.Context, dx, dy, dz int, opts Options) (Graph, error) {
+		return generate(ctx, MakeGrid(dx, dy, dz), opts)
+	}
+}
+
+func TestOptions_Progress(t *testing.T) {
+	sizes := []struct{ dx, dy, dz int }{{10, 10, 10}, {1, 1, 1}}
+	for _, tt := range contextGenerators {
+		for _, size := range sizes {
+			total := size.dx * size.dy * size.dz
+			t.Run(fmt.Sprintf("%s %dx%dx%d", tt.name, size.dx, size.dy, size.dz), func(t *testing.T) {
+				last := 0
+				opts := Options{Progress: func(added, got int) {
+					if added <= last || added > got || got != total {
+						t.Fatalf("Progress(%d, %d) after %d", added, got, last)
+					}
+					last = added
+				}}
+				maze, err := tt.generate(context.Background(), size.dx, size.dy, size.dz, opts)
+				if err != nil {
+					t.Fatalf("err = %v", err)
+				}
+				if last != total {
+					t.Errorf("last progress was %d, want %d", last, total)
+				}
+				if maze.NodeCount() != total {
+					t.Errorf("maze has %d nodes, want %d", maze.NodeCount(), total)
+				}
+			})
+		}
+	}
+}
+
+func TestOptions_MaxNodes(t *testing.T) {
+	for _, tt := range contextGenerators {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.generate(context.Background(), 10, 10, 10, Options{MaxNodes: 999})
+			var be *BudgetError
+			if !errors.As(err, &be) || be.Nodes != 1000 || be.MaxNodes != 999 {
+				t.Errorf("err = %v, want *BudgetError", err)
+			}
+			if _, err := tt.generate(context.Background(), 10, 10, 10, Options{MaxNodes: 1000}); err != nil {
+				t.Errorf("err = %v at the limit, want nil", err)
+			}
+		})
+	}
+
+	// the budget is checked before the grid is allocated.
+	if _, err := MakeGridContext(context.Background(), 1024, 1024, 1024, Options{MaxNodes: 1 << 20}); err == nil {
+		t.Errorf("MakeGridContext(1024, 1024, 1024) did not exceed the budget")
+	}
+}
+
+func TestOptions_cancel(t *testing.T) {
+	for _, tt := range contextGenerators {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			if _, err := tt.generate(ctx, 10, 10, 10, Options{}); err != context.Canceled {
+				t.Errorf("err = %v with a canceled context, want %v", err, context.Canceled)
+			}
+
+			// cancel part way through.
+			ctx, cancel = context.WithCancel(context.Background())
+			defer cancel()
+			stoppedAt := 0
+			opts := Options{Progress: func(added, total int) {
+				if stoppedAt == 0 && added >= total/2 {
+					stoppedAt = added
+					cancel()
+				}
+			}}
+			maze, err := tt.generate(ctx, 40, 40, 40, opts)
+			if err != context.Canceled {
+				t.Errorf("err = %v after canceling, want %v", err, context.Canceled)
+			}
+			if maze != nil && tt.name != "Eller" {
+				t.Errorf("got a maze after canceling")
+			}
+		})
+	}
+}
+
+func TestEllerContext_endless(t *testing.T) {
+	err := EllerContext(context.Background(), 10, 1, 0, func(Layer) bool { return true }, Options{MaxNodes: 1000})
+	var be *BudgetError
+	if !errors.As(err, &be) {
+		t.Errorf("err = %v, want *BudgetError", err)
+	}
+}
@@ -0,0 +1,80 @@
+package maze
+
+// PCG is a random number generator using the PCG-XSH-RR algorithm with 64
+// bits of state and 32 bits of output, as described by M.E. O'Neill. Its
+// output for a given seed and stream is fixed and will not change between
+// versions of this package or of Go, unlike math/rand's, so a stored seed
+// will always reproduce the same maze.
+// see: https://www.pcg-random.org/
+type PCG struct {
+	state uint64
+	inc   uint64 // the stream. always odd.
+}
+
+const pcgMultiplier = 6364136223846793005
+
+// NewPCG makes a PCG with the given seed and stream. Generators with
+// different streams produce different sequences from the same seed.
+func NewPCG(seed, stream uint64) *PCG {
+	p := &PCG{}
+	p.Seed(seed, stream)
+	return p
+}
+
+// Seed resets the generator to the given seed and stream. It is the same as
+// pcg32_srandom_r() in the reference implementation.
+func (p *PCG) Seed(seed, stream uint64) {
+	p.state = 0
+	p.inc = stream<<1 | 1
+	p.Uint32()
+	p.state += seed
+	p.Uint32()
+}
+
+// Uint32 returns the next 32 bits from the generator.
+func (p *PCG) Uint32() uint32 {
+	old := p.state
+	p.state = old*pcgMultiplier + p.inc
+	xorshifted := uint32(((old >> 18) ^ old) >> 27)
+	rot := uint32(old >> 59)
+	return xorshifted>>rot | xorshifted<<((-rot)&31)
+}
+
+// Uint64 returns the next 64 bits from the generator, made from two calls to
+// Uint32 with the first giving the high bits.
+func (p *PCG) Uint64() uint64 {
+	hi := uint64(p.Uint32())
+	return hi<<32 | uint64(p.Uint32())
+}
+
+// Intn returns a number in [0,n). It panics if n <= 0. Numbers are drawn
+// from Uint32 (or Uint64 if n does not fit in 32 bits) and those below
+// 2^k mod n are rejected, so the result is unbiased.
+func (p *PCG) Intn(n int) int {
+	if n <= 0 {
+		panic("maze: invalid argument to PCG.Intn")
+	}
+	if uint64(n) <= 1<<32-1 {
+		bound := uint32(n)
+		threshold := -bound % bound
+		for {
+			if r := p.Uint32(); r >= threshold {
+				return int(r % bound)
+			}
+		}
+	}
+
+	bound := uint64(n)
+	threshold := -bound % bound
+	for {
+		if r := p.Uint64(); r >= threshold {
+			return int(r % bound)
+		}
+	}
+}
+
+// Float64 returns a number in [0.0,1.0) made from the top 53 bits of
+// Uint64.
+func (p *PCG) Float64() float64 {
+	return float64(p.Uint64()>>11) / (1 << 53)
+}
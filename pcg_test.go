@@ -0,0 +1,106 @@
+package maze
+
+import (
+	"reflect"
+	"testing"
+)
+
+// The values in these tests are golden: if they change, mazes made from
+// stored seeds will change too.
+
+func TestPCG_Uint32(t *testing.T) {
+	// the output of pcg32-demo from the reference implementation.
+	p := NewPCG(42, 54)
+	want := []uint32{0xa15c02b7, 0x7b47f409, 0xba1d3330, 0x83d2f293, 0xbfa4784b, 0xcbed606e}
+	for i, w := range want {
+		if got := p.Uint32(); got != w {
+			t.Errorf("PCG.Uint32() #%d = %#08x, want %#08x", i, got, w)
+		}
+	}
+}
+
+func TestPCG_Intn(t *testing.T) {
+	p := NewPCG(2024, 0)
+	want := []int{880, 722, 325, 121, 913, 148}
+	got := make([]int, len(want))
+	for i := range got {
+		got[i] = p.Intn(1000)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PCG.Intn(1000) = %v, want %v", got, want)
+	}
+
+	// large n uses 64 bits.
+	p = NewPCG(2024, 0)
+	if got, want := p.Intn(1<<40), 585192981978; got != want {
+		t.Errorf("PCG.Intn(1<<40) = %v, want %v", got, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("PCG.Intn(0) did not panic")
+		}
+	}()
+	p.Intn(0)
+}
+
+func TestPCG_Float64(t *testing.T) {
+	p := NewPCG(2024, 0)
+	want := []float64{0.6426945049899766, 0.18710580788320352}
+	for i, w := range want {
+		if got := p.Float64(); got != w {
+			t.Errorf("PCG.Float64() #%d = %v, want %v", i, got, w)
+		}
+	}
+}
+
+func TestPCG_golden(t *testing.T) {
+	tests := []struct {
+		name     string
+		generate func(RNG) Graph
+		want     string
+	}{
+		{name: "Backtracker", generate: func(rng RNG) Graph { return BacktrackerRand(MakeGrid(5, 5, 1), rng) },
+			want: "[0-1 1-2 1-6 10-15 11-12 11-16 12-13 14-19 15-20 16-21 17-22 18-19 18-23 2-3 20-21 22-23 23-24 3-4 4-9 5-10 6-7 8-13 8-9 9-14]"},
+		{name: "Kruskal", generate: func(rng RNG) Graph { return KruskalRand(MakeGrid(5, 5, 1), rng) },
+			want: "[0-5 1-2 10-11 10-15 13-14 15-16 15-20 16-17 16-21 17-18 18-19 19-24 2-3 21-22 23-24 3-4 4-9 5-10 6-11 6-7 7-12 7-8 8-13 8-9]"},
+		{name: "Wilson", generate: func(rng RNG) Graph { return WilsonRand(MakeGrid(5, 5, 1), rng) },
+			want: "[0-1 0-5 10-11 12-13 13-14 13-18 15-16 15-20 16-21 17-22 18-19 19-24 2-3 21-22 22-23 23-24 3-4 3-8 5-6 6-11 6-7 7-12 7-8 9-14]"},
+		{name: "Prim", generate: func(rng RNG) Graph { return PrimRand(MakeGrid(5, 5, 1), rng) },
+			want: "[0-1 0-5 10-11 10-15 12-13 12-17 14-19 15-16 15-20 16-17 16-21 17-18 17-22 18-19 2-7 22-23 23-24 3-4 3-8 5-10 5-6 6-7 8-13 8-9]"},
+		{name: "PrimWeighted", generate: func(rng RNG) Graph { return PrimWeightedRand(MakeGrid(5, 5, 1), RandomWeights(rng), rng) },
+			want: "[0-5 1-2 1-6 10-11 10-15 11-12 12-17 13-14 15-20 16-17 17-22 18-19 18-23 20-21 22-23 23-24 3-8 4-9 5-6 6-11 6-7 7-8 8-13 8-9]"},
+		{name: "AldousBroder", generate: func(rng RNG) Graph { return AldousBroderRand(MakeGrid(5, 5, 1), rng) },
+			want: "[0-1 0-5 1-2 1-6 10-11 12-17 13-14 14-19 15-20 16-21 17-18 18-19 19-24 2-3 20-21 21-22 22-23 23-24 3-8 4-9 5-10 7-12 7-8 8-9]"},
+		{name: "HuntAndKill", generate: func(rng RNG) Graph { return HuntAndKillRand(MakeGrid(5, 5, 1), rng) },
+			want: "[0-1 0-5 1-2 1-6 10-15 11-12 11-16 12-13 14-19 15-20 16-21 17-22 18-19 18-23 19-24 2-3 20-21 22-23 3-4 5-10 6-7 8-13 8-9 9-14]"},
+		{name: "GrowingTree", generate: func(rng RNG) Graph {
+			return GrowingTreeRand(MakeGrid(5, 5, 1), SelectMix(SelectNewest, 0.5, SelectRandom), rng)
+		}, want: "[0-1 1-6 10-11 10-15 11-12 13-14 13-18 14-19 15-16 17-18 17-22 19-24 2-3 2-7 20-21 21-22 23-24 3-4 4-9 5-10 5-6 7-12 7-8 9-14]"},
+		{name: "Eller", generate: func(rng RNG) Graph {
+			maze := NewMapGraph()
+			EllerRand(3, 3, 3, func(l Layer) bool {
+				for _, e := range append(l.Edges, l.Next...) {
+					maze.AddEdge(e.A, e.B)
+				}
+				return true
+			}, rng)
+			return maze
+		}, want: "[0-1 0-9 1-10 1-2 1-4 12-13 13-16 14-23 15-24 16-17 17-26 18-21 19-22 2-11 20-23 21-22 21-24 22-25 23-26 3-12 4-13 4-5 6-15 7-16 7-8 9-18]"},
+		{name: "BinaryTree", generate: func(rng RNG) Graph { return BinaryTreeRand(5, 5, 1, Bias{}, rng) },
+			want: "[0-1 1-2 10-11 11-16 12-13 13-14 14-19 15-16 16-21 17-22 18-23 19-24 2-7 20-21 21-22 22-23 23-24 3-8 4-9 5-6 6-7 7-12 8-13 9-14]"},
+		{name: "Sidewinder", generate: func(rng RNG) Graph { return SidewinderRand(5, 5, 1, Bias{}, rng) },
+			want: "[0-1 1-2 1-6 10-11 11-16 12-17 13-14 13-18 15-16 15-20 17-18 17-22 19-24 20-21 21-22 22-23 23-24 3-8 4-9 5-10 6-11 7-12 8-9 9-14]"},
+		{name: "RecursiveDivision", generate: func(rng RNG) Graph { return RecursiveDivisionRand(5, 5, 1, rng) },
+			want: "[0-1 0-5 10-11 10-15 12-13 13-14 15-20 16-21 17-18 17-22 18-19 18-23 19-24 2-3 2-7 20-21 21-22 3-4 3-8 5-10 5-6 6-7 8-13 8-9]"},
+		{name: "Tiled", generate: func(rng RNG) Graph { return TiledRand(5, 5, 1, 3, WilsonContext, rng) },
+			want: "[0-1 1-2 10-11 11-12 12-17 13-14 15-16 15-20 16-17 18-19 18-23 19-24 2-3 2-7 20-21 21-22 22-23 3-8 4-9 5-10 5-6 6-7 8-9 9-14]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := treeKey(tt.generate(NewPCG(2024, 0))); got != tt.want {
+				t.Errorf("maze from seed 2024 = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package maze
+
+// The functions in this file pick start and goal nodes for a maze.
+
+// Diameter finds the length of the longest shortest path in maze, and the
+// nodes at its ends. If maze is perfect, it takes two breadth first searches
+// for each component: the node farthest from any other is at one end of a
+// longest path. Otherwise a search is made from every node. An empty maze
+// has a diameter of 0 and nil ends.
+func Diameter(maze Graph) (length int, start, goal Node) {
+	return farthestPair(maze, func(Node) bool { return true })
+}
+
+// FarthestPair picks the two nodes of maze with the longest shortest path
+// between them. See Diameter.
+func FarthestPair(maze Graph) (start, goal Node) {
+	_, start, goal = Diameter(maze)
+	return start, goal
+}
+
+// FarthestBoundaryPair is FarthestPair for a maze made from MakeGrid(dx, dy,
+// dz), where start and goal are on its outside. Axes which are one node long
+// are ignored, so for a 2D maze the nodes are on its edges.
+func FarthestBoundaryPair(maze Graph, dx, dy, dz int) (start, goal Node) {
+	dims := [3]int{dx, dy, dz}
+	_, start, goal = farthestPair(maze, func(n Node) bool {
+		var p [3]int
+		p[0], p[1], p[2] = gridPosition(n, dx, dy)
+		for i := range p {
+			if dims[i] > 1 && (p[i] == 0 || p[i] == dims[i]-1) {
+				return true
+			}
+		}
+		return dims == [3]int{1, 1, 1}
+	})
+	return start, goal
+}
+
+// PairNear picks two nodes of maze with a shortest path between them as
+// close to target long as possible. If no path is that long, the ends of the
+// longest are returned. If target is negative, or maze is empty, nil is
+// returned for both.
+func PairNear(maze Graph, target int) (start, goal Node) {
+	return PairNearRand(maze, target, defaultRNG)
+}
+
+// PairNearRand is PairNear using rng as the source of randomness.
+func PairNearRand(maze Graph, target int, rng RNG) (start, goal Node) {
+	if target < 0 {
+		return nil, nil
+	}
+
+	// try a random start first. it has nodes at every distance up to that
+	// of the node farthest from it.
+	start = RandomNode(maze, rng)
+	if start == nil {
+		return nil, nil
+	}
+	dist := DistanceMap(maze, start)
+	var exact NodeSlice
+	for _, n := range maze.Nodes() {
+		if d, in := dist[n]; in && d == target {
+			exact = exact.Append(n)
+		}
+	}
+	if len(exact) > 0 {
+		return start, exact[rng.Intn(len(exact))]
+	}
+
+	// every length up to the diameter is found along the longest path.
+	length, start, goal := Diameter(maze)
+	if target >= length {
+		return start, goal
+	}
+	path, _ := Solve(maze, start, goal)
+	return start, path[target]
+}
+
+// farthestPair finds the longest shortest path in maze between two nodes
+// for which ok returns true.
+func farthestPair(maze Graph, ok func(Node) bool) (length int, start, goal Node) {
+	components := Components(maze)
+	perfect := len(Edges(maze)) == maze.NodeCount()-len(components)
+
+	length = -1
+	for _, c := range components {
+		var candidates NodeSlice
+		for _, n := range c {
+			if ok(n) {
+				candidates = candidates.Append(n)
+			}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+
+		from := candidates
+		if perfect {
+			// in a tree this holds for any set of nodes, not just all of them.
+			a, _ := farthestFrom(maze, candidates[0], candidates)
+			from = NodeSlice{a}
+		}
+		for _, a := range from {
+			if b, d := farthestFrom(maze, a, candidates); d > length {
+				length, start, goal = d, a, b
+			}
+		}
+	}
+
+	if length < 0 {
+		return 0, nil, nil
+	}
+	return length, start, goal
+}
+
+// farthestFrom finds the node of among farthest from n, and its distance.
+// among must be in the same component as n.
+func farthestFrom(maze Graph, n Node, among NodeSlice) (far Node, length int) {
+	dist := DistanceMap(maze, n)
+	far = n
+	for _, m := range among {
+		if dist[m] > length {
+			far, length = m, dist[m]
+		}
+	}
+	return far, length
+}
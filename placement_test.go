@@ -0,0 +1,128 @@
+package maze
+
+import (
+	"testing"
+)
+
+// bruteDiameter finds the longest shortest path between nodes of g for which
+// ok returns true by searching from each of them.
+func bruteDiameter(g Graph, ok func(Node) bool) int {
+	longest := 0
+	for _, a := range g.Nodes() {
+		if !ok(a) {
+			continue
+		}
+		for b, d := range DistanceMap(g, a) {
+			if ok(b) && d > longest {
+				longest = d
+			}
+		}
+	}
+	return longest
+}
+
+func all(Node) bool { return true }
+
+func placementTests() []struct {
+	name string
+	maze Graph
+} {
+	grid := MakeGrid(9, 7, 1)
+	braided := BacktrackerRand(grid, NewPCG(9, 0))
+	BraidRand(braided, grid, 0.5, NewPCG(9, 0))
+	looped := WilsonRand(MakeGrid(5, 4, 3), NewPCG(9, 0))
+	AddLoopsRand(looped, MakeGrid(5, 4, 3), 10, LoopsLong, NewPCG(9, 0))
+
+	return []struct {
+		name string
+		maze Graph
+	}{
+		{"perfect", WilsonRand(grid, NewPCG(9, 0))},
+		{"perfect 3D", KruskalRand(MakeGrid(5, 4, 3), NewPCG(9, 0))},
+		{"braided", braided},
+		{"looped 3D", looped},
+		{"open grid", grid},
+		{"forest", BacktrackerRand(maskedGrid(), NewPCG(9, 0))},
+		{"single node", mapgraph{0: NodeSlice{}}},
+	}
+}
+
+func TestDiameter(t *testing.T) {
+	for _, tt := range placementTests() {
+		t.Run(tt.name, func(t *testing.T) {
+			length, start, goal := Diameter(tt.maze)
+			if want := bruteDiameter(tt.maze, all); length != want {
+				t.Errorf("Diameter() = %d, want %d", length, want)
+			}
+			if d := distance(tt.maze, start, goal); d != length {
+				t.Errorf("distance from (%v) to (%v) is %d, want %d", start, goal, d, length)
+			}
+		})
+	}
+
+	if length, start, goal := Diameter(NewMapGraph()); length != 0 || start != nil || goal != nil {
+		t.Errorf("Diameter(empty) = %d, %v, %v", length, start, goal)
+	}
+}
+
+func TestFarthestBoundaryPair(t *testing.T) {
+	tests := []struct {
+		name       string
+		dx, dy, dz int
+	}{
+		{"2D", 9, 7, 1},
+		{"3D", 5, 4, 3},
+		{"corridor", 10, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grid := MakeGrid(tt.dx, tt.dy, tt.dz)
+			for _, maze := range []Graph{WilsonRand(grid, NewPCG(2, 0)), grid} {
+				dims := [3]int{tt.dx, tt.dy, tt.dz}
+				boundary := func(n Node) bool {
+					var p [3]int
+					p[0], p[1], p[2] = gridPosition(n, tt.dx, tt.dy)
+					for i := range p {
+						if dims[i] > 1 && (p[i] == 0 || p[i] == dims[i]-1) {
+							return true
+						}
+					}
+					return false
+				}
+
+				start, goal := FarthestBoundaryPair(maze, tt.dx, tt.dy, tt.dz)
+				if !boundary(start) || !boundary(goal) {
+					t.Errorf("(%v) and (%v) are not both on the boundary", start, goal)
+				}
+				if d, want := distance(maze, start, goal), bruteDiameter(maze, boundary); d != want {
+					t.Errorf("distance from (%v) to (%v) is %d, want %d", start, goal, d, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPairNear(t *testing.T) {
+	for _, tt := range placementTests() {
+		t.Run(tt.name, func(t *testing.T) {
+			length, _, _ := Diameter(tt.maze)
+			for target := 0; target <= length+2; target++ {
+				start, goal := PairNearRand(tt.maze, target, NewPCG(uint64(target), 0))
+				want := target
+				if want > length {
+					want = length
+				}
+				if d := distance(tt.maze, start, goal); d != want {
+					t.Errorf("PairNear(%d) = (%v), (%v) at distance %d, want %d", target, start, goal, d, want)
+				}
+			}
+		})
+	}
+
+	if start, goal := PairNear(NewMapGraph(), 3); start != nil || goal != nil {
+		t.Errorf("PairNear(empty) = %v, %v, want nil, nil", start, goal)
+	}
+	if start, goal := PairNear(MakeGrid(5, 5, 1), -3); start != nil || goal != nil {
+		t.Errorf("PairNear(-3) = %v, %v, want nil, nil", start, goal)
+	}
+}
@@ -0,0 +1,60 @@
+package maze
+
+import (
+	"sync"
+	"time"
+)
+
+// RNG is a source of random numbers. Each algorithm in this package has a
+// variant which takes an RNG, so that a maze can be reproduced from a seed.
+// *PCG and *rand.Rand implement RNG. Use PCG for seeds which are stored,
+// since math/rand's output may change between versions of Go.
+type RNG interface {
+	// Intn returns a number in [0,n). It panics if n <= 0.
+	Intn(n int) int
+	// Float64 returns a number in [0.0,1.0).
+	Float64() float64
+}
+
+// lockedRNG is an RNG which can be shared by goroutines.
+type lockedRNG struct {
+	mu  sync.Mutex
+	src *PCG
+}
+
+func (r *lockedRNG) Intn(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.src.Intn(n)
+}
+
+func (r *lockedRNG) Float64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.src.Float64()
+}
+
+// defaultRNG is used by the algorithms which are not given an RNG. It is a
+// PCG seeded from the time the program started.
+var defaultRNG RNG = &lockedRNG{src: NewPCG(uint64(time.Now().UnixNano()), 0)}
+
+// shuffle randomizes the order of n elements using rng. swap exchanges the
+// elements with indexes i and j.
+func shuffle(rng RNG, n int, swap func(i, j int)) {
+	// Fisher-Yates, done here rather than with rand.Shuffle so that it only
+	// depends on rng.Intn.
+	for i := n - 1; i > 0; i-- {
+		swap(i, rng.Intn(i+1))
+	}
+}
+
+// RandomNode picks a node from g using rng. Unlike g.RandomNode(), the choice
+// depends only on rng and the nodes in g, so it can be reproduced. It returns
+// nil if g is empty.
+func RandomNode(g Graph, rng RNG) Node {
+	nodes := g.Nodes()
+	if len(nodes) == 0 {
+		return nil
+	}
+	return nodes[rng.Intn(len(nodes))]
+}
@@ -0,0 +1,45 @@
+package maze
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func Test_shuffle(t *testing.T) {
+	s := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	shuffle(rand.New(rand.NewSource(1)), len(s), func(i, j int) {
+		s[i], s[j] = s[j], s[i]
+	})
+	sorted := append([]int(nil), s...)
+	sort.Ints(sorted)
+	if !reflect.DeepEqual(sorted, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}) {
+		t.Errorf("shuffle() = %v is not a permutation", s)
+	}
+}
+
+func TestRandomNode(t *testing.T) {
+	tests := []struct {
+		name string
+		g    Graph
+	}{
+		{name: "empty", g: NewMapGraph()},
+		{name: "normal", g: constructGraph1()},
+		{name: "grid", g: MakeGrid(10, 10, 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(0); seed < 10; seed++ {
+				a := RandomNode(tt.g, rand.New(rand.NewSource(seed)))
+				b := RandomNode(tt.g, rand.New(rand.NewSource(seed)))
+				if a != b {
+					t.Errorf("RandomNode() = %v and %v with the same seed", a, b)
+				}
+				if (tt.g.NodeCount() == 0) != (a == nil) || a != nil && !tt.g.Has(a) {
+					t.Errorf("RandomNode() = %v", a)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package maze
+
+import (
+	"fmt"
+)
+
+// The functions in this file find paths through mazes. They work on any
+// Graph, whether it is perfect or not.
+
+// Solve finds a shortest path through maze from start to goal using a
+// breadth first search. The path begins with start and ends with goal. A
+// *MissingNodeError is returned if start or goal is not in maze, and an
+// *UnreachableError if there is no path between them.
+func Solve(maze Graph, start, goal Node) (NodeSlice, error) {
+	if err := checkEnds(maze, start, goal); err != nil {
+		return nil, err
+	}
+
+	prev := map[Node]Node{start: nil}
+	queue := NodeSlice{start}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if n == goal {
+			return tracePath(prev, start, goal), nil
+		}
+		for _, neighbor := range maze.Neighbors(n) {
+			if _, seen := prev[neighbor]; !seen {
+				prev[neighbor] = n
+				queue = queue.Append(neighbor)
+			}
+		}
+	}
+	return nil, &UnreachableError{Start: start, Goal: goal}
+}
+
+// checkEnds returns a *MissingNodeError if start or goal is not in maze.
+func checkEnds(maze Graph, start, goal Node) error {
+	for _, n := range []Node{start, goal} {
+		if !maze.Has(n) {
+			return &MissingNodeError{Node: n}
+		}
+	}
+	return nil
+}
+
+// tracePath follows the links in prev back from goal to start, and returns
+// the nodes passed in order from start to goal.
+func tracePath(prev map[Node]Node, start, goal Node) NodeSlice {
+	var path NodeSlice
+	for n := goal; n != start; n = prev[n] {
+		path = path.Append(n)
+	}
+	path = path.Append(start)
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// MissingNodeError is returned when a node given to a solver is not in the
+// maze.
+type MissingNodeError struct {
+	Node Node
+}
+
+func (e *MissingNodeError) Error() string {
+	return fmt.Sprintf("maze: node (%v) is not in the maze", e.Node)
+}
+
+// UnreachableError is returned when there is no path from Start to Goal.
+type UnreachableError struct {
+	Start, Goal Node
+}
+
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("maze: no path from (%v) to (%v)", e.Start, e.Goal)
+}
+
+// DeadEndFill fills the dead ends of maze, other than start and goal, until
+// none are left: each node with one neighbor is removed, which may make its
+// neighbor a dead end in turn. Nodes left with no neighbors, which are in
+// other parts of maze than start and goal, are filled too. maze is not
+// changed. It returns what is left, along with the filled nodes in the order
+// they were filled. For a perfect maze what is left is the path from start
+// to goal. Loops are never filled, so in an imperfect maze any loop is left,
+// along with the passages joining it to start and goal, even if it is off
+// every path between them. CulDeSacFill removes those too.
+// see: http://www.astrolog.org/labyrnth/algrithm.htm
+func DeadEndFill(maze Graph, start, goal Node) (reduced Graph, filled NodeSlice) {
+	reduced = NewMapGraph()
+	reduced.Add(maze.Nodes()...)
+	for _, e := range Edges(maze) {
+		reduced.AddEdge(e.A, e.B)
+	}
+
+	fillable := func(n Node) bool {
+		return n != start && n != goal && len(reduced.Neighbors(n)) <= 1
+	}
+	var queue NodeSlice
+	for _, n := range reduced.Nodes() {
+		if fillable(n) {
+			queue = queue.Append(n)
+		}
+	}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if !reduced.Has(n) {
+			continue // filled already
+		}
+
+		neighbors := append(NodeSlice(nil), reduced.Neighbors(n)...)
+		reduced.Remove(n)
+		filled = filled.Append(n)
+		for _, neighbor := range neighbors {
+			if fillable(neighbor) {
+				queue = queue.Append(neighbor)
+			}
+		}
+	}
+	return reduced, filled
+}
+
+// CulDeSacFill fills every node and passage of maze which isn't on a path
+// from start to goal that visits each node at most once. Beyond the dead ends
+// filled by DeadEndFill, that includes cul-de-sacs: loops, and whatever else,
+// which join the rest of the maze at a single node. maze is not changed. It
+// returns what is left, along with the filled nodes in the order of
+// maze.Nodes(). If goal can't be reached from start, only they are left.
+//
+// A passage is on such a path exactly when it is in the same biconnected
+// component as an edge between start and goal, added if maze doesn't have
+// one. The components are found with Tarjan's algorithm.
+// see: http://www.astrolog.org/labyrnth/algrithm.htm
+func CulDeSacFill(maze Graph, start, goal Node) (reduced Graph, filled NodeSlice) {
+	reduced = NewMapGraph()
+	for _, n := range []Node{start, goal} {
+		if maze.Has(n) {
+			reduced.Add(n)
+		}
+	}
+	if start != goal && maze.Has(start) && maze.Has(goal) {
+		for _, e := range pathBlock(maze, start, goal) {
+			reduced.AddEdge(e.A, e.B)
+		}
+	}
+
+	for _, n := range maze.Nodes() {
+		if !reduced.Has(n) {
+			filled = filled.Append(n)
+		}
+	}
+	return reduced, filled
+}
+
+// pathBlock finds the edges of maze in the biconnected component which
+// contains the edge between start and goal, after adding it to maze if it
+// isn't there.
+func pathBlock(maze Graph, start, goal Node) []Edge {
+	added := !maze.HasEdge(start, goal)
+	neighbors := func(n Node) NodeSlice {
+		ns := maze.Neighbors(n)
+		switch {
+		case added && n == start:
+			return append(ns[:len(ns):len(ns)], goal)
+		case added && n == goal:
+			return append(ns[:len(ns):len(ns)], start)
+		}
+		return ns
+	}
+
+	// disc is the order each node was found in by the depth first search,
+	// and low the earliest node found which it or a descendant has an edge to.
+	disc := make(map[Node]int)
+	low := make(map[Node]int)
+	var edges []Edge // edges of components not yet finished
+
+	type frame struct {
+		n, parent Node
+		i         int // next neighbor to look at
+	}
+	stack := []frame{{n: start}}
+	disc[start], low[start] = 0, 0
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		if ns := neighbors(f.n); f.i < len(ns) {
+			next := ns[f.i]
+			f.i++
+			if d, seen := disc[next]; !seen {
+				disc[next], low[next] = len(disc), len(disc)
+				edges = append(edges, Edge{A: f.n, B: next})
+				stack = append(stack, frame{n: next, parent: f.n})
+			} else if next != f.parent && d < disc[f.n] {
+				edges = append(edges, Edge{A: f.n, B: next}) // back edge
+				if d < low[f.n] {
+					low[f.n] = d
+				}
+			}
+			continue
+		}
+
+		n := f.n
+		stack = stack[:len(stack)-1]
+		if len(stack) == 0 {
+			break
+		}
+		parent := stack[len(stack)-1].n
+		if low[n] < low[parent] {
+			low[parent] = low[n]
+		}
+		if low[n] < disc[parent] {
+			continue // n's component goes on above parent
+		}
+
+		// the edges from parent to n onward form a component.
+		i := len(edges) - 1
+		for edges[i] != (Edge{A: parent, B: n}) {
+			i--
+		}
+		block := edges[i:]
+		edges = edges[:i]
+
+		found := false
+		var kept []Edge
+		for _, e := range block {
+			if (e.A == start && e.B == goal) || (e.A == goal && e.B == start) {
+				found = true
+				if added {
+					continue // not really in maze
+				}
+			}
+			kept = append(kept, e)
+		}
+		if found {
+			return kept
+		}
+	}
+	return nil
+}
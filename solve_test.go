@@ -0,0 +1,211 @@
+package maze
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// checkPath fails t if path isn't a walk through maze from start to goal.
+func checkPath(t *testing.T, maze Graph, path NodeSlice, start, goal Node) {
+	t.Helper()
+	if len(path) == 0 || path[0] != start || path[len(path)-1] != goal {
+		t.Fatalf("path %v doesn't go from (%v) to (%v)", path, start, goal)
+	}
+	for i := 1; i < len(path); i++ {
+		if !maze.HasEdge(path[i-1], path[i]) {
+			t.Fatalf("path %v uses (%v)-(%v) which is not in maze", path, path[i-1], path[i])
+		}
+	}
+}
+
+func TestSolve(t *testing.T) {
+	grid := MakeGrid(10, 10, 1)
+	braided := BacktrackerRand(grid, NewPCG(4, 0))
+	BraidRand(braided, grid, 1, NewPCG(4, 0))
+
+	tests := []struct {
+		name        string
+		maze        Graph
+		start, goal Node
+		length      int // nodes in the path
+	}{
+		{"grid", grid, 0, 99, 19},
+		{"3D grid", MakeGrid(4, 4, 4), 0, 63, 10},
+		{"same node", grid, 5, 5, 1},
+		{"corridor", Backtracker(MakeGrid(10, 1, 1)), 9, 0, 10},
+		{"braided", braided, 0, 99, distance(braided, 0, 99) + 1},
+		{"isolated node", mapgraph{0: NodeSlice{}}, 0, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := Solve(tt.maze, tt.start, tt.goal)
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			checkPath(t, tt.maze, path, tt.start, tt.goal)
+			if len(path) != tt.length {
+				t.Errorf("path has %d nodes, want %d", len(path), tt.length)
+			}
+		})
+	}
+}
+
+func TestSolve_errors(t *testing.T) {
+	g := maskedGrid()
+	far := g.Nodes()[len(g.Nodes())-1]
+
+	path, err := Solve(g, 0, -1)
+	var missing *MissingNodeError
+	if path != nil || !errors.As(err, &missing) || missing.Node != -1 {
+		t.Errorf("Solve() = %v, %v, want *MissingNodeError for (-1)", path, err)
+	}
+
+	path, err = Solve(g, "start", 0)
+	if path != nil || !errors.As(err, &missing) || missing.Node != "start" {
+		t.Errorf("Solve() = %v, %v, want *MissingNodeError for (start)", path, err)
+	}
+
+	var unreachable *UnreachableError
+	path, err = Solve(g, 0, far)
+	want := &UnreachableError{Start: 0, Goal: far}
+	if path != nil || !errors.As(err, &unreachable) || !reflect.DeepEqual(unreachable, want) {
+		t.Errorf("Solve() = %v, %v, want %v", path, err, want)
+	}
+}
+
+func TestDeadEndFill(t *testing.T) {
+	grid := MakeGrid(12, 12, 1)
+	braided := BacktrackerRand(grid, NewPCG(5, 0))
+	BraidRand(braided, grid, 0.3, NewPCG(5, 0))
+
+	tests := []struct {
+		name        string
+		maze        Graph
+		start, goal Node
+	}{
+		{"perfect", WilsonRand(grid, NewPCG(5, 0)), 0, 143},
+		{"perfect 3D", KruskalRand(MakeGrid(5, 5, 5), NewPCG(5, 0)), 62, 0},
+		{"braided", braided, 0, 143},
+		{"same node", Backtracker(grid), 7, 7},
+		{"forest", BacktrackerRand(maskedGrid(), NewPCG(5, 0)), 2, 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, edges := tt.maze.NodeCount(), len(Edges(tt.maze))
+			reduced, filled := DeadEndFill(tt.maze, tt.start, tt.goal)
+
+			if tt.maze.NodeCount() != nodes || len(Edges(tt.maze)) != edges {
+				t.Fatalf("DeadEndFill() changed maze")
+			}
+			if reduced.NodeCount()+len(filled) != nodes {
+				t.Errorf("%d nodes left and %d filled, want %d in all",
+					reduced.NodeCount(), len(filled), nodes)
+			}
+			for _, n := range filled {
+				if reduced.Has(n) || n == tt.start || n == tt.goal {
+					t.Errorf("(%v) was filled but shouldn't be", n)
+				}
+			}
+			for _, n := range reduced.Nodes() {
+				if n != tt.start && n != tt.goal && len(reduced.Neighbors(n)) < 2 {
+					t.Errorf("(%v) is a dead end which wasn't filled", n)
+				}
+			}
+
+			// the shortest path must survive. in a perfect maze, it's all
+			// that's left.
+			path, err := Solve(tt.maze, tt.start, tt.goal)
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			checkPath(t, reduced, path, tt.start, tt.goal)
+			if perfect := edges == nodes-len(Components(tt.maze)); perfect && reduced.NodeCount() != len(path) {
+				t.Errorf("%d nodes left, want the %d on the path", reduced.NodeCount(), len(path))
+			}
+		})
+	}
+}
+
+// simplePathEdges finds the edges of g on some path from start to goal which
+// doesn't visit a node twice, by trying every such path.
+func simplePathEdges(g Graph, start, goal Node) map[Edge]bool {
+	on := make(map[Edge]bool)
+	path := NodeSlice{start}
+	var walk func(n Node)
+	walk = func(n Node) {
+		if n == goal {
+			for i := 1; i < len(path); i++ {
+				on[Edge{A: path[i-1], B: path[i]}] = true
+				on[Edge{A: path[i], B: path[i-1]}] = true
+			}
+			return
+		}
+		for _, next := range g.Neighbors(n) {
+			if !path.Has(next) {
+				path = path.Append(next)
+				walk(next)
+				path = path[:len(path)-1]
+			}
+		}
+	}
+	walk(start)
+	return on
+}
+
+func TestCulDeSacFill(t *testing.T) {
+	// a loop hanging off the path from 0 to 2, which DeadEndFill leaves.
+	hanging := NewMapGraph()
+	hanging.AddEdge(0, 1)
+	hanging.AddEdge(1, 2)
+	hanging.AddEdge(1, 3)
+	hanging.AddEdge(3, 4)
+	hanging.AddEdge(4, 5)
+	hanging.AddEdge(5, 1)
+	if _, filled := DeadEndFill(hanging, 0, 2); len(filled) != 0 {
+		t.Fatalf("DeadEndFill() filled %v", filled)
+	}
+
+	grid := MakeGrid(5, 4, 1)
+	braided := BacktrackerRand(grid, NewPCG(3, 0))
+	BraidRand(braided, grid, 0.6, NewPCG(3, 0))
+	looped := KruskalRand(grid, NewPCG(3, 0))
+	AddLoopsRand(looped, grid, 3, LoopsShort, NewPCG(3, 0))
+
+	tests := []struct {
+		name        string
+		maze        Graph
+		start, goal Node
+	}{
+		{"hanging loop", hanging, 0, 2},
+		{"perfect", WilsonRand(grid, NewPCG(3, 0)), 0, 19},
+		{"braided", braided, 0, 19},
+		{"braided middle", braided, 6, 13},
+		{"looped", looped, 4, 15},
+		{"open grid", MakeGrid(3, 3, 1), 0, 8},
+		{"neighbors", MakeGrid(3, 3, 1), 4, 5},
+		{"same node", braided, 7, 7},
+		{"unreachable", Backtracker(maskedGrid()), 2, 99},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reduced, filled := CulDeSacFill(tt.maze, tt.start, tt.goal)
+			want := simplePathEdges(tt.maze, tt.start, tt.goal)
+
+			for _, e := range Edges(tt.maze) {
+				if reduced.HasEdge(e.A, e.B) != want[e] {
+					t.Errorf("reduced.HasEdge(%v, %v) = %v, want %v", e.A, e.B, !want[e], want[e])
+				}
+			}
+			for _, n := range []Node{tt.start, tt.goal} {
+				if !reduced.Has(n) || filled.Has(n) {
+					t.Errorf("(%v) was filled", n)
+				}
+			}
+			if reduced.NodeCount()+len(filled) != tt.maze.NodeCount() {
+				t.Errorf("%d nodes left and %d filled, want %d in all",
+					reduced.NodeCount(), len(filled), tt.maze.NodeCount())
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package maze
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// Tiled makes a maze from a dx by dy by dz grid like MakeGrid's, by splitting
+// it into tiles of size nodes along each axis and making a maze in each tile
+// at the same time with generate, such as WilsonContext. The tiles are then
+// joined by a random spanning tree of the tile grid, with one random passage
+// between each pair of tiles joined by the tree, so the result is a perfect
+// maze. It is not a uniform spanning tree even if generate makes those, and
+// the tile borders may be visible in the result. dx, dy, and dz should be in
+// [1,1024].
+func Tiled(dx, dy, dz, size int, generate func(context.Context, Graph, Options) (Graph, error)) Graph {
+	return TiledRand(dx, dy, dz, size, generate, defaultRNG)
+}
+
+// TiledRand is Tiled using rng as the source of randomness. Each tile is
+// given its own RNG seeded from rng, so the maze depends only on rng and not
+// on how the tiles are scheduled.
+func TiledRand(dx, dy, dz, size int, generate func(context.Context, Graph, Options) (Graph, error), rng RNG) Graph {
+	maze, _ := TiledContext(context.Background(), dx, dy, dz, size, generate, Options{Rand: rng})
+	return maze
+}
+
+// TiledContext is Tiled controlled by ctx and opts. ctx is passed to
+// generate, and the first error it returns for any tile is returned.
+// Progress is reported as the tiles are joined.
+func TiledContext(ctx context.Context, dx, dy, dz, size int, generate func(context.Context, Graph, Options) (Graph, error), opts Options) (Graph, error) {
+	max := 1024
+	dx = clamp(dx, 1, max)
+	dy = clamp(dy, 1, max)
+	dz = clamp(dz, 1, max)
+	size = clamp(size, 1, max)
+
+	return build(ctx, dx*dy*dz, opts, func(b *builder) {
+		tiled(b, dx, dy, dz, size, generate)
+	})
+}
+
+func tiled(b *builder, dx, dy, dz, size int, generate func(context.Context, Graph, Options) (Graph, error)) {
+	dims := [3]int{dx, dy, dz}
+	var count [3]int // tiles along each axis
+	for i := range dims {
+		count[i] = (dims[i] + size - 1) / size
+	}
+	tiles := count[0] * count[1] * count[2]
+	node := func(p [3]int) Node {
+		return Node(ThreeToOne(p[0], p[1], p[2], dx, dy))
+	}
+	bounds := func(t Node) box {
+		var r box
+		r.lo[0], r.lo[1], r.lo[2] = gridPosition(t, count[0], count[1])
+		for i := range r.lo {
+			r.lo[i] *= size
+			r.hi[i] = r.lo[i] + size
+			if r.hi[i] > dims[i] {
+				r.hi[i] = dims[i]
+			}
+		}
+		return r
+	}
+
+	// each tile's RNG is a stream of one seed chosen before any tile is
+	// started, so the result doesn't depend on which goroutine runs first.
+	seed := uint64(b.rng.Intn(1<<30))<<30 | uint64(b.rng.Intn(1<<30))
+
+	ctx, cancel := context.WithCancel(b.ctx)
+	defer cancel()
+	var (
+		mazes = make([]Graph, tiles)
+		jobs  = make(chan int)
+		wg    sync.WaitGroup
+		once  sync.Once
+		err   error
+	)
+	for w := 0; w < runtime.GOMAXPROCS(0) && w < tiles; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range jobs {
+				g := makeTile(bounds(Node(t)), node)
+				maze, e := generate(ctx, g, Options{Rand: NewPCG(seed, uint64(t))})
+				if e != nil {
+					once.Do(func() {
+						err = e
+						cancel() // no point making the other tiles
+					})
+					continue
+				}
+				mazes[t] = maze
+			}
+		}()
+	}
+	for t := 0; t < tiles; t++ {
+		jobs <- t
+	}
+	close(jobs)
+	wg.Wait()
+	if err != nil {
+		b.err = err
+		return
+	}
+
+	for _, maze := range mazes {
+		for _, e := range Edges(maze) {
+			if b.stopped() {
+				return
+			}
+			b.carve(e.A, e.B)
+		}
+		b.add(maze.Nodes()...) // a tile of one node has no edges
+	}
+
+	// join the tiles with a passage through a random node on the face each
+	// pair shares.
+	for _, e := range Edges(WilsonRand(MakeGrid(count[0], count[1], count[2]), b.rng)) {
+		if b.stopped() {
+			return
+		}
+		near, far := bounds(e.A), bounds(e.B)
+		if nodeLess(e.B, e.A) {
+			near, far = far, near
+		}
+		axis := 0
+		for near.lo[axis] == far.lo[axis] {
+			axis++
+		}
+
+		face := near
+		face.lo[axis] = near.hi[axis] - 1
+		var p [3]int
+		for i := range p {
+			p[i] = face.lo[i] + b.rng.Intn(face.hi[i]-face.lo[i])
+		}
+		q := p
+		q[axis]++
+		b.carve(node(p), node(q))
+	}
+}
+
+// makeTile makes the part of the grid inside r, naming each node by its
+// position in the whole grid.
+func makeTile(r box, node func([3]int) Node) Graph {
+	g := NewMapGraph()
+	var p [3]int
+	for p[2] = r.lo[2]; p[2] < r.hi[2]; p[2]++ {
+		for p[1] = r.lo[1]; p[1] < r.hi[1]; p[1]++ {
+			for p[0] = r.lo[0]; p[0] < r.hi[0]; p[0]++ {
+				n := node(p)
+				g.Add(n)
+				for i := range p {
+					q := p
+					q[i]++
+					if q[i] < r.hi[i] {
+						g.AddEdge(n, node(q))
+					}
+				}
+			}
+		}
+	}
+	return g
+}
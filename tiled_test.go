@@ -0,0 +1,63 @@
+package maze
+
+import (
+	"context"
+	"runtime"
+	"testing"
+)
+
+func TestTiled(t *testing.T) {
+	tests := []struct {
+		name       string
+		dx, dy, dz int
+		size       int
+	}{
+		{"one tile", 5, 5, 1, 8},
+		{"even tiles", 12, 8, 1, 4},
+		{"uneven tiles", 13, 7, 1, 4},
+		{"3D", 6, 5, 7, 3},
+		{"single nodes", 4, 3, 2, 1},
+		{"line", 20, 1, 1, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maze := TiledRand(tt.dx, tt.dy, tt.dz, tt.size, WilsonContext, NewPCG(1, 0))
+			checkPerfect(t, MakeGrid(tt.dx, tt.dy, tt.dz), maze)
+		})
+	}
+}
+
+func TestTiled_deterministic(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+
+	var want string
+	for _, procs := range []int{1, 2, 8} {
+		runtime.GOMAXPROCS(procs)
+		got := treeKey(TiledRand(40, 30, 2, 7, BacktrackerContext, NewPCG(5, 0)))
+		if want == "" {
+			want = got
+		} else if got != want {
+			t.Errorf("maze with GOMAXPROCS=%d differs from GOMAXPROCS=1", procs)
+		}
+	}
+}
+
+func TestTiledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	maze, err := TiledContext(ctx, 10, 10, 1, 3, WilsonContext, Options{})
+	if maze != nil || err != context.Canceled {
+		t.Errorf("TiledContext() = %v, %v, want nil, %v", maze, err, context.Canceled)
+	}
+
+	// cancel while the tiles are being made.
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	generate := func(ctx context.Context, g Graph, opts Options) (Graph, error) {
+		cancel()
+		return WilsonContext(ctx, g, opts)
+	}
+	if _, err := TiledContext(ctx, 10, 10, 1, 3, generate, Options{}); err != context.Canceled {
+		t.Errorf("err = %v, want %v", err, context.Canceled)
+	}
+}